@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
+	"html"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,20 +21,30 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
+// maxIncidentTextLength caps incident notes and root causes
+const maxIncidentTextLength = 2000
+
 func main() {
 	// Load environment variables
 	_ = godotenv.Load()
 	
 	// Initialize services
-	storageService, err := services.NewStorageService()
+	storageService, err := services.NewStore()
 	if err != nil {
 		fmt.Printf("❌ Failed to initialize storage service: %v\n", err)
 		os.Exit(1)
 	}
-	monitorService := services.NewMonitorService()
 	sslService := services.NewSSLService()
-	cleanupService := services.NewCleanupService(storageService.GetStatusesCollection())
+	domainService := services.NewDomainService(storageService, services.DomainConfigFromEnv())
+	monitorService := services.NewMonitorService(sslService)
+	cleanupService := services.NewCleanupService(storageService)
 	discordService := services.NewDiscordService()
+	notificationService := services.NewNotificationService(discordService)
+	heartbeatService := services.NewHeartbeatService(storageService)
+	incidentService := services.NewIncidentService(storageService)
+	ackLinkService := services.NewAckLinkService()
+	maintenanceService := services.NewMaintenanceService(storageService)
+	escalationService := services.NewEscalationService(storageService, notificationService, ackLinkService, maintenanceService)
 
 	// Load any existing data
 	// if err := storageService.LoadFromFiles(); err != nil {
@@ -80,109 +94,344 @@ func main() {
 	// Create a new cron scheduler
 	c := cron.New()
 
-	// Track previous statuses to avoid spam
-	previousStatuses := make(map[string]bool)
+	// Function to send an up/down/flapping alert for a website's incident
+	sendStateAlert := func(website models.Website, status models.WebsiteStatus, state *models.MonitorState, user *models.User, alert string, incident *models.Incident) {
+		// Incidents following an escalation policy notify its steps instead
+		// of every channel; recoveries go to the steps that were notified
+		channels := services.UserChannels(user)
+		if incident != nil {
+			if policy := services.FindEscalationPolicy(user, incident.EscalationPolicyID); policy != nil {
+				if alert == models.AlertDown {
+					if err := escalationService.Escalate(*incident, website, user, &status, time.Now()); err != nil {
+						fmt.Printf("  ⚠️ Failed to escalate alert: %v\n", err)
+					}
+					return
+				}
+				// Flapping is reported to at least the first step
+				steps := incident.EscalationStep
+				if steps == 0 && (alert == models.AlertFlapping || alert == models.AlertStabilized) {
+					steps = 1
+				}
+				channels = services.PolicyChannels(user, *policy, steps)
+			}
+		}
+
+		// Send the alert
+		notification := services.Alert{
+			Website:  website,
+			Type:     alert,
+			Status:   status,
+			Time:     time.Now(),
+			Incident: incident,
+			State:    state,
+		}
+		if incident != nil && incident.Status == models.IncidentOpen {
+			notification.AckURL = ackLinkService.URL(incident.ID)
+		}
+		if err := notificationService.Notify(channels, notification); err != nil {
+			fmt.Printf("  ⚠️ Failed to send alert: %v\n", err)
+		}
+	}
+
+	// Function to record a check result and alert on confirmed state changes.
+	// Shared by scheduled checks and heartbeat pings.
+	processResult := func(website models.Website, status models.WebsiteStatus) {
+		// Checks during a maintenance window are recorded but never alert
+		window, err := maintenanceService.Active(website, time.Now())
+		if err != nil {
+			fmt.Printf("  Error checking maintenance windows: %v\n", err)
+		}
+		status.Maintenance = window != nil
+
+		// Record the result in the persisted state, which decides whether an
+		// alert is due so restarts and other instances don't re-alert
+		var alert, latencyAlert string
+		var state *models.MonitorState
+		if window == nil {
+			state, err = storageService.UpdateMonitorState(website.ID, func(state *models.MonitorState) {
+				alert = services.ApplyCheckResult(state, website, status.IsUp, time.Now())
+				latencyAlert = services.ApplyLatency(state, website, status, time.Now())
+			})
+			if err != nil {
+				fmt.Printf("  Error updating monitor state: %v\n", err)
+			}
+		}
+
+		// The status carries the confirmed degraded state, so readers such
+		// as the public status page don't need the monitor state
+		status.Degraded = state != nil && state.Degraded && state.IsUp
+		if err := storageService.SaveStatus(status); err != nil {
+			fmt.Printf("  Error saving status: %v\n", err)
+		}
+		if window != nil {
+			fmt.Printf("  🔧 %s is in maintenance window %q, alerts suppressed\n", website.Name, window.Name)
+			return
+		}
+		if state == nil {
+			return
+		}
+
+		// Keep the website's incident in step with the confirmed state
+		incident, err := incidentService.Record(website, status, alert, state.IsUp)
+		if err != nil {
+			fmt.Printf("  Error recording incident: %v\n", err)
+		} else if incident != nil && alert != "" {
+			fmt.Printf("  🧾 Incident %s for %s is %s (%s)\n", incident.ID, website.Name, incident.Status, alert)
+		}
+
+		if alert == "" && latencyAlert == "" {
+			return
+		}
+
+		user, err := storageService.GetUser(website.UserID)
+		if err != nil {
+			fmt.Printf("  Error fetching user for alert: %v\n", err)
+			return
+		}
+
+		if alert != "" {
+			sendStateAlert(website, status, state, user, alert, incident)
+		}
+
+		// Latency alerts have no incident
+		if latencyAlert != "" {
+			if err := notificationService.Notify(services.AlertChannels(user, website), services.Alert{
+				Website: website,
+				Type:    latencyAlert,
+				Status:  status,
+				Time:    time.Now(),
+				State:   state,
+			}); err != nil {
+				fmt.Printf("  ⚠️ Failed to send latency alert: %v\n", err)
+			}
+		}
+	}
+
+	// Function to check a single website (uptime/latency)
+	checkWebsite := func(website models.Website) {
+		// Push monitors are never requested; only check for a missed ping
+		if website.Type == models.MonitorPush {
+			status, err := heartbeatService.Evaluate(website)
+			if err != nil {
+				fmt.Printf("❌ Error evaluating heartbeat for %s: %v\n", website.Name, err)
+				return
+			}
+			if status != nil {
+				fmt.Printf("💔 %s missed its heartbeat: %s\n", website.Name, status.FailedAssertion)
+				processResult(website, *status)
+			}
+			return
+		}
+
+		fmt.Printf("Checking %s (%s)...\n", website.Name, website.URL)
 
-	// Function to check all websites (uptime/latency)
-	checkAllWebsites := func() {
+		status, err := monitorService.CheckWithRetries(website)
+		if err != nil {
+			fmt.Printf("  Error after %d attempt(s): %v\n", status.Attempts, err)
+		} else {
+			fmt.Printf("  Status: %v (Code: %d, Response time: %d ms, Attempts: %d)\n",
+				status.IsUp, status.StatusCode, status.ResponseTime, status.Attempts)
+		}
+		processResult(website, status)
+	}
+
+	// Checks run on a bounded worker pool so slow targets can't stall others
+	workerPool := services.NewWorkerPool(services.WorkerPoolConfigFromEnv(), checkWebsite)
+	workerPool.Start()
+
+	// Each website gets its own check loop based on its Interval
+	scheduler := services.NewSchedulerService(func(website models.Website) {
+		workerPool.Submit(website)
+	})
+
+	// Reload websites from storage so adds and deletes (including those
+	// made by other instances) are picked up without a restart
+	syncSchedule := func() {
 		websites, err := storageService.GetWebsites()
 		if err != nil {
 			fmt.Printf("❌ Error fetching websites: %v\n", err)
 			return
 		}
-		fmt.Printf("🔍 Checking %d websites...\n", len(websites))
+		scheduler.Sync(websites)
+	}
 
-		for _, website := range websites {
-			fmt.Printf("Checking %s (%s)...\n", website.Name, website.URL)
+	// Schedule all websites on start
+	syncSchedule()
+
+	// Function to check a website's certificate and alert when it crosses
+	// one of the owner's expiry thresholds or the check starts failing
+	checkSSL := func(w models.Website) {
+		info, err := sslService.CheckWebsite(w)
+		if err != nil || info == nil {
+			return
+		}
+		info.WebsiteID = w.ID
+		if err := storageService.SaveSSL(*info); err != nil {
+			fmt.Printf("⚠️ Failed to save SSL info for %s: %v\n", w.Name, err)
+		}
+		if !services.ServesTLS(w) {
+			return
+		}
 
-			status, err := monitorService.CheckWebsite(website)
+		// Keep every certificate served, noting how it replaced the last one
+		var replaced *models.CertificateRecord
+		if info.Fingerprint != "" {
+			history, err := storageService.GetCertificateHistory(w.ID)
 			if err != nil {
-				fmt.Printf("  Error: %v\n", err)
-				// Only alert if status changed from up to down
-				if prevStatus, exists := previousStatuses[website.ID]; !exists || prevStatus {
-					// Get user's Discord webhook and send alert
-					if webhookURL, err := storageService.GetUserDiscordWebhook(website.UserID); err == nil && webhookURL != "" {
-						discordService.SendAlertToWebhook(webhookURL, website, false, 0)
-					}
-				}
-				previousStatuses[website.ID] = false
+				fmt.Printf("⚠️ Failed to get certificate history for %s: %v\n", w.Name, err)
 			} else {
-				if err := storageService.SaveStatus(status); err != nil {
-					fmt.Printf("  Error saving status: %v\n", err)
-				}
-
-				// Only alert on status changes
-				if prevStatus, exists := previousStatuses[website.ID]; exists && prevStatus != status.IsUp {
-					// Get user's Discord webhook and send alert
-					if webhookURL, err := storageService.GetUserDiscordWebhook(website.UserID); err == nil && webhookURL != "" {
-						discordService.SendAlertToWebhook(webhookURL, website, status.IsUp, status.ResponseTime)
-					}
-				} else if !exists && !status.IsUp {
-					// First check and it's down
-					if webhookURL, err := storageService.GetUserDiscordWebhook(website.UserID); err == nil && webhookURL != "" {
-						discordService.SendAlertToWebhook(webhookURL, website, false, status.ResponseTime)
+				// Compare with the last certificate served rather than every
+				// one seen, so swapping back to an older certificate is noticed
+				record := services.NewCertificateRecord(*info, services.LatestCertificate(history))
+				if _, err := storageService.RecordCertificate(record); err != nil {
+					fmt.Printf("⚠️ Failed to record certificate for %s: %v\n", w.Name, err)
+				} else if record.PreviousFingerprint != "" {
+					fmt.Printf("🔁 Certificate of %s changed: %s\n", w.Name, strings.Join(record.Changes, "; "))
+					if record.Unexpected {
+						replaced = &record
 					}
 				}
-				previousStatuses[website.ID] = status.IsUp
+			}
+		}
 
-				fmt.Printf("  Status: %v (Code: %d, Response time: %d ms)\n",
-					status.IsUp, status.StatusCode, status.ResponseTime)
+		// Certificates swapped during maintenance are expected
+		if window, err := maintenanceService.Active(w, time.Now()); err != nil || window != nil {
+			return
+		}
+
+		user, err := storageService.GetUser(w.UserID)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to get user for SSL alert of %s: %v\n", w.Name, err)
+			return
+		}
+		if replaced != nil {
+			if err := notificationService.Notify(services.AlertChannels(user, w), services.Alert{
+				Website:     w,
+				Type:        models.AlertSSLChanged,
+				Time:        time.Now(),
+				SSL:         info,
+				Certificate: replaced,
+			}); err != nil {
+				fmt.Printf("⚠️ Failed to send certificate change alert: %v\n", err)
 			}
 		}
+		var alert string
+		state, err := storageService.UpdateMonitorState(w.ID, func(state *models.MonitorState) {
+			alert = services.ApplySSLResult(state, *info, services.SSLExpiryThresholds(user))
+		})
+		if err != nil {
+			fmt.Printf("⚠️ Failed to update SSL state for %s: %v\n", w.Name, err)
+			return
+		}
+		if alert == "" {
+			return
+		}
 
-		fmt.Println("Next check in 60 seconds...")
+		fmt.Printf("🔒 SSL alert for %s: %s (%d days left)\n", w.Name, alert, info.DaysLeft)
+		if err := notificationService.Notify(services.AlertChannels(user, w), services.Alert{
+			Website: w,
+			Type:    alert,
+			Time:    time.Now(),
+			State:   state,
+			SSL:     info,
+		}); err != nil {
+			fmt.Printf("⚠️ Failed to send SSL alert: %v\n", err)
+		}
 	}
 
-	// Run an initial uptime check on start
-	checkAllWebsites()
-
-	// Run SSL checks one time at startup
-	go func() {
+	// Function to check every website's certificate
+	checkAllSSL := func() {
 		websites, err := storageService.GetWebsites()
 		if err != nil {
 			fmt.Printf("⚠️ Failed to get websites for SSL check: %v\n", err)
 			return
 		}
 		for _, w := range websites {
-			info, err := sslService.Check(w.URL)
-			if err != nil || info == nil {
-				continue
-			}
-			info.WebsiteID = w.ID
-			if err := storageService.SaveSSL(*info); err != nil {
-				fmt.Printf("⚠️ Failed to save SSL info for %s: %v\n", w.Name, err)
-			}
+			checkSSL(w)
+		}
+	}
+
+	// Function to check the registration of a website's domain and alert
+	// when it crosses one of the owner's expiry thresholds. Websites under
+	// the same domain share one lookup.
+	checkDomain := func(w models.Website) {
+		domain, err := services.ApexDomain(w)
+		if err != nil {
+			return
+		}
+		info, err := domainService.Check(domain)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to save domain info for %s: %v\n", domain, err)
+		}
+		if window, err := maintenanceService.Active(w, time.Now()); err != nil || window != nil {
+			return
+		}
+
+		user, err := storageService.GetUser(w.UserID)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to get user for domain alert of %s: %v\n", w.Name, err)
+			return
+		}
+		// Websites under the same apex domain share the alert state, so
+		// each of the user's domains alerts once
+		var alert string
+		state, err := storageService.UpdateMonitorState(services.DomainStateID(w.UserID, domain), func(state *models.MonitorState) {
+			alert = services.ApplyDomainResult(state, *info, services.DomainExpiryThresholds(user))
+		})
+		if err != nil {
+			fmt.Printf("⚠️ Failed to update domain state for %s: %v\n", w.Name, err)
+			return
+		}
+		if alert == "" {
+			return
 		}
-	}()
 
-	// Schedule regular uptime checks every minute
-	c.AddFunc("@every 1m", checkAllWebsites)
+		fmt.Printf("📅 Domain alert for %s: %s expires in %d days\n", w.Name, domain, info.DaysLeft)
+		if err := notificationService.Notify(services.AlertChannels(user, w), services.Alert{
+			Website: w,
+			Type:    alert,
+			Time:    time.Now(),
+			State:   state,
+			Domain:  info,
+		}); err != nil {
+			fmt.Printf("⚠️ Failed to send domain alert: %v\n", err)
+		}
+	}
 
-	// Schedule daily SSL checks (once a day is enough)
-	c.AddFunc("@daily", func() {
+	// Function to check every website's domain
+	checkAllDomains := func() {
 		websites, err := storageService.GetWebsites()
 		if err != nil {
-			fmt.Printf("⚠️ Failed to get websites for daily SSL check: %v\n", err)
+			fmt.Printf("⚠️ Failed to get websites for domain check: %v\n", err)
 			return
 		}
 		for _, w := range websites {
-			info, err := sslService.Check(w.URL)
-			if err != nil || info == nil {
-				continue
-			}
-			info.WebsiteID = w.ID
-			if err := storageService.SaveSSL(*info); err != nil {
-				fmt.Printf("⚠️ Failed to save SSL info for %s: %v\n", w.Name, err)
-			}
+			checkDomain(w)
 		}
-	})
+	}
+
+	// Run SSL and domain checks one time at startup
+	go checkAllSSL()
+	go checkAllDomains()
+
+	// Re-sync the per-website schedules regularly
+	c.AddFunc("@every 30s", syncSchedule)
+
+	// Send due escalation steps and reminders for unacknowledged incidents
+	c.AddFunc("@every 1m", escalationService.Run)
+
+	// Schedule daily SSL checks (once a day is enough)
+	c.AddFunc("@daily", checkAllSSL)
+
+	// Schedule daily domain expiry checks
+	c.AddFunc("@daily", checkAllDomains)
 
 	// Schedule weekly cleanup (keep 30 days of data)
 	c.AddFunc("@weekly", func() {
 		if err := cleanupService.CleanupOldStatuses(30); err != nil {
 			fmt.Printf("⚠️ Failed to cleanup old statuses: %v\n", err)
 		}
-		if err := cleanupService.CleanupOrphanedStatuses(storageService.GetWebsitesCollection()); err != nil {
+		if err := cleanupService.CleanupOrphanedStatuses(); err != nil {
 			fmt.Printf("⚠️ Failed to cleanup orphaned statuses: %v\n", err)
 		}
 	})
@@ -214,6 +463,7 @@ func main() {
 			"status": "ok",
 			"time": time.Now(),
 			"uptime": time.Since(time.Now()).String(),
+			"checks": workerPool.Stats(),
 		})
 	})
 
@@ -254,7 +504,7 @@ func main() {
 		}
 
 		// Compute now and store
-		info, err := sslService.Check(site.URL)
+		info, err := sslService.CheckWebsite(*site)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 		}
@@ -263,6 +513,41 @@ func main() {
 		return c.JSON(info)
 	})
 
+	// Get the certificates a website has served (protected)
+	app.Get("/api/websites/:id/ssl/history", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		userID := c.Locals("user_id").(string)
+		if _, err := storageService.GetWebsiteByUser(id, userID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Website not found"})
+		}
+
+		history, err := storageService.GetCertificateHistory(id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch certificate history", "details": err.Error()})
+		}
+		return c.JSON(history)
+	})
+
+	// Get the registration of a website's domain (protected)
+	app.Get("/api/websites/:id/domain", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		userID := c.Locals("user_id").(string)
+		site, err := storageService.GetWebsiteByUser(id, userID)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Website not found"})
+		}
+
+		domain, err := services.ApexDomain(*site)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Website has no domain", "details": err.Error()})
+		}
+		info, err := domainService.Check(domain)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to save domain info for %s: %v\n", domain, err)
+		}
+		return c.JSON(info)
+	})
+
 	// Get SSL summary (protected)
 	app.Get("/api/ssl/summary", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
 		userID := c.Locals("user_id").(string)
@@ -343,19 +628,50 @@ func main() {
 			})
 		}
 
+		if website.Type == "" {
+			website.Type = models.MonitorHTTP
+		}
+
+		// Push monitors get a secret ping URL instead of a target
+		if website.Type == models.MonitorPush {
+			token, err := services.GeneratePushToken()
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": "Failed to create push monitor"})
+			}
+			website.PushToken = token
+			website.URL = services.PushPath(token)
+		}
+
 		// Validate input data
-		if validationErrors := utils.ValidateWebsite(website.Name, website.URL); len(validationErrors) > 0 {
+		if validationErrors := utils.ValidateWebsite(website); len(validationErrors) > 0 {
 			return c.Status(400).JSON(fiber.Map{
 				"error": "Validation failed",
 				"validation_errors": validationErrors,
 			})
 		}
 
+		// An escalation policy must be one of the user's
+		if website.EscalationPolicyID != "" {
+			user, err := storageService.GetUser(userID)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch user"})
+			}
+			if services.FindEscalationPolicy(user, website.EscalationPolicyID) == nil {
+				return c.Status(400).JSON(fiber.Map{
+					"error": "Validation failed",
+					"validation_errors": []utils.ValidationError{{
+						Field:   "escalation_policy_id",
+						Message: "Unknown escalation policy",
+					}},
+				})
+			}
+		}
+
 		// Check for duplicate URL within user's websites (using normalized URLs)
 		normalizedNewURL := utils.NormalizeURL(website.URL)
 		for _, existing := range existingWebsites {
 			normalizedExistingURL := utils.NormalizeURL(existing.URL)
-			if normalizedExistingURL == normalizedNewURL {
+			if normalizedExistingURL == normalizedNewURL && services.MonitorType(existing) == services.MonitorType(website) {
 				return c.Status(400).JSON(fiber.Map{
 					"error": "Validation failed",
 					"validation_errors": []utils.ValidationError{{
@@ -370,25 +686,25 @@ func main() {
 		if website.ID == "" {
 			website.ID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
-		// Enforce minimum interval (60 seconds)
-		if website.Interval == 0 || website.Interval < 60 {
-			website.Interval = 60
+		// Default interval; out of range ones were rejected above
+		if website.Interval == 0 {
+			website.Interval = int(services.DefaultCheckInterval.Seconds())
 		}
 		
 		// Set user ID
 		website.UserID = userID
+		website.CreatedAt = time.Now().Unix()
 
 		if err := storageService.SaveWebsite(website); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to save website"})
 		}
 
+		// Start checking right away instead of waiting for the next sync
+		scheduler.Schedule(website)
+
 		// Kick an immediate SSL check (non-blocking) and upsert result
-		go func(w models.Website) {
-			if info, err := sslService.Check(w.URL); err == nil && info != nil {
-				info.WebsiteID = w.ID
-				_ = storageService.SaveSSL(*info)
-			}
-		}(website)
+		go checkSSL(website)
+		go checkDomain(website)
 
 		return c.Status(201).JSON(website)
 	})
@@ -400,11 +716,270 @@ func main() {
 		if err := storageService.DeleteWebsiteByUser(id, userID); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to delete website"})
 		}
+		scheduler.Unschedule(id)
+		return c.JSON(fiber.Map{"success": true})
+	})
+
+	// === INCIDENT ENDPOINTS ===
+	// Incidents group a website's failed checks from the first confirmed
+	// down until recovery
+
+	// List the user's incidents, optionally filtered by status (protected)
+	app.Get("/api/incidents", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		status := c.Query("status")
+		if status != "" && status != models.IncidentOpen && status != models.IncidentResolved {
+			return c.Status(400).JSON(fiber.Map{"error": "status must be open or resolved"})
+		}
+		incidents, err := storageService.GetIncidentsByUser(userID, status, int64(c.QueryInt("limit", 100)))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch incidents", "details": err.Error()})
+		}
+		return c.JSON(incidents)
+	})
+
+	// Incident count, downtime and MTTR over the last ?days (default 30) (protected)
+	app.Get("/api/incidents/summary", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		days := c.QueryInt("days", 30)
+		if days <= 0 || days > 365 {
+			return c.Status(400).JSON(fiber.Map{"error": "days must be between 1 and 365"})
+		}
+		since := time.Now().AddDate(0, 0, -days)
+		incidents, err := storageService.GetIncidentsSince(userID, c.Query("website_id"), since.Unix())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch incidents", "details": err.Error()})
+		}
+		return c.JSON(services.SummarizeIncidents(incidents, since))
+	})
+
+	// Get a single incident with its timeline (protected)
+	app.Get("/api/incidents/:id", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		incident, err := storageService.GetIncidentByUser(c.Params("id"), userID)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Incident not found"})
+		}
+		return c.JSON(incident)
+	})
+
+	// List a website's incidents (protected)
+	app.Get("/api/websites/:id/incidents", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		userID := c.Locals("user_id").(string)
+		if _, err := storageService.GetWebsiteByUser(id, userID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Website not found"})
+		}
+		incidents, err := storageService.GetIncidentsByWebsite(id, int64(c.QueryInt("limit", 100)))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch incidents", "details": err.Error()})
+		}
+		return c.JSON(incidents)
+	})
+
+	// Acknowledge an open incident (protected)
+	app.Post("/api/incidents/:id/ack", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		id := c.Params("id")
+		if _, err := storageService.GetIncidentByUser(id, userID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Incident not found"})
+		}
+		incident, acknowledged, err := storageService.AcknowledgeIncident(id, userID, time.Now().Unix())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to acknowledge incident", "details": err.Error()})
+		}
+		if !acknowledged && incident.Status != models.IncidentOpen {
+			return c.Status(409).JSON(fiber.Map{"error": "Incident is already resolved", "incident": incident})
+		}
+		return c.JSON(incident)
+	})
+
+	// Add a note to an incident (protected)
+	app.Post("/api/incidents/:id/notes", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		id := c.Params("id")
+		if _, err := storageService.GetIncidentByUser(id, userID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Incident not found"})
+		}
+
+		var requestBody struct {
+			Text string `json:"text"`
+		}
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		text := strings.TrimSpace(requestBody.Text)
+		if text == "" || len(text) > maxIncidentTextLength {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("text must be 1-%d characters", maxIncidentTextLength)})
+		}
+
+		note := models.IncidentNote{At: time.Now().Unix(), Author: userID, Text: text}
+		if err := storageService.AddIncidentNote(id, note); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to add note", "details": err.Error()})
+		}
+		return c.Status(201).JSON(note)
+	})
+
+	// Set the root cause of an incident (protected)
+	app.Put("/api/incidents/:id/root-cause", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		id := c.Params("id")
+		if _, err := storageService.GetIncidentByUser(id, userID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Incident not found"})
+		}
+
+		var requestBody struct {
+			RootCause string `json:"root_cause"`
+		}
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		rootCause := strings.TrimSpace(requestBody.RootCause)
+		if len(rootCause) > maxIncidentTextLength {
+			return c.Status(400).JSON(fiber.Map{"error": fmt.Sprintf("root_cause must be at most %d characters", maxIncidentTextLength)})
+		}
+
+		if err := storageService.SetIncidentRootCause(id, rootCause, time.Now().Unix()); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to set root cause", "details": err.Error()})
+		}
+		return c.JSON(fiber.Map{"success": true, "root_cause": rootCause})
+	})
+
+	// One-click acknowledge links from alerts (signed, no login). Opening the
+	// link only shows a confirmation page so link previews in chat apps
+	// don't acknowledge on their own; the page's button POSTs back.
+	app.Get("/api/ack/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		if err := ackLinkService.Verify(id, c.Query("expires"), c.Query("sig")); err != nil {
+			return c.Status(403).Type("html").SendString(ackPage("Cannot acknowledge", err.Error(), ""))
+		}
+		return c.Type("html").SendString(ackPage("Acknowledge incident?",
+			"Let the team know someone is handling this incident.", c.OriginalURL()))
+	})
+	app.Post("/api/ack/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		if err := ackLinkService.Verify(id, c.Query("expires"), c.Query("sig")); err != nil {
+			return c.Status(403).Type("html").SendString(ackPage("Cannot acknowledge", err.Error(), ""))
+		}
+		incident, acknowledged, err := storageService.AcknowledgeIncident(id, "alert link", time.Now().Unix())
+		if err != nil {
+			return c.Status(500).Type("html").SendString(ackPage("Cannot acknowledge", "Something went wrong, please try again.", ""))
+		}
+		switch {
+		case acknowledged:
+			return c.Type("html").SendString(ackPage("Acknowledged",
+				fmt.Sprintf("The incident on %s is acknowledged.", incident.WebsiteName), ""))
+		case incident.Status != models.IncidentOpen:
+			return c.Type("html").SendString(ackPage("Already resolved",
+				fmt.Sprintf("%s is back up.", incident.WebsiteName), ""))
+		default:
+			return c.Type("html").SendString(ackPage("Already acknowledged",
+				fmt.Sprintf("The incident on %s was acknowledged at %s.", incident.WebsiteName,
+					time.Unix(incident.AcknowledgedAt, 0).UTC().Format(time.RFC1123)), ""))
+		}
+	})
+
+	// === MAINTENANCE WINDOW ENDPOINTS ===
+	// During a window checks still run, but alerts are suppressed and the
+	// results are excluded from uptime
+
+	// maintenanceResponse adds the computed schedule to a window
+	type maintenanceResponse struct {
+		models.MaintenanceWindow
+		Active    bool  `json:"active"`
+		NextStart int64 `json:"next_start,omitempty"`
+		NextEnd   int64 `json:"next_end,omitempty"`
+	}
+	toMaintenanceResponse := func(window models.MaintenanceWindow) maintenanceResponse {
+		now := time.Now()
+		response := maintenanceResponse{MaintenanceWindow: window, Active: services.MaintenanceActive(window, now)}
+		if start, end := services.NextMaintenance(window, now); !start.IsZero() {
+			response.NextStart, response.NextEnd = start.Unix(), end.Unix()
+		}
+		return response
+	}
+
+	// saveMaintenanceWindow validates and stores a window sent by a user
+	saveMaintenanceWindow := func(c *fiber.Ctx, id string, createdAt int64) error {
+		userID := c.Locals("user_id").(string)
+		var window models.MaintenanceWindow
+		if err := c.BodyParser(&window); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body", "details": err.Error()})
+		}
+
+		validationErrors := utils.ValidateMaintenanceWindow(window)
+		for i, websiteID := range window.WebsiteIDs {
+			if _, err := storageService.GetWebsiteByUser(websiteID, userID); err != nil {
+				validationErrors = append(validationErrors, utils.ValidationError{
+					Field:   fmt.Sprintf("website_ids[%d]", i),
+					Message: "Website not found",
+				})
+			}
+		}
+		if len(validationErrors) > 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"error":             "Validation failed",
+				"validation_errors": validationErrors,
+			})
+		}
+
+		window.ID = id
+		window.UserID = userID
+		window.CreatedAt = createdAt
+		if err := storageService.SaveMaintenanceWindow(window); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to save maintenance window"})
+		}
+		maintenanceService.Invalidate(userID)
+		return c.JSON(toMaintenanceResponse(window))
+	}
+
+	// List maintenance windows (protected)
+	app.Get("/api/maintenance", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		windows, err := storageService.GetMaintenanceWindowsByUser(userID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch maintenance windows", "details": err.Error()})
+		}
+		responses := make([]maintenanceResponse, len(windows))
+		for i, window := range windows {
+			responses[i] = toMaintenanceResponse(window)
+		}
+		return c.JSON(responses)
+	})
+
+	// Add a maintenance window (protected)
+	app.Post("/api/maintenance", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		return saveMaintenanceWindow(c, fmt.Sprintf("%d", time.Now().UnixNano()), time.Now().Unix())
+	})
+
+	// Replace a maintenance window (protected)
+	app.Put("/api/maintenance/:id", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		id := c.Params("id")
+		windows, err := storageService.GetMaintenanceWindowsByUser(userID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch maintenance windows"})
+		}
+		for _, window := range windows {
+			if window.ID == id {
+				return saveMaintenanceWindow(c, id, window.CreatedAt)
+			}
+		}
+		return c.Status(404).JSON(fiber.Map{"error": "Maintenance window not found"})
+	})
+
+	// Delete a maintenance window (protected)
+	app.Delete("/api/maintenance/:id", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(string)
+		if err := storageService.DeleteMaintenanceWindowByUser(c.Params("id"), userID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Maintenance window not found"})
+		}
+		maintenanceService.Invalidate(userID)
 		return c.JSON(fiber.Map{"success": true})
 	})
 
 	// === USER SETTINGS ENDPOINTS ===
-	// These endpoints manage user-specific settings like Discord webhooks
+	// These endpoints manage user-specific settings like notification channels
 
 	// Get user settings (protected)
 	app.Get("/api/user/settings", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
@@ -416,17 +991,30 @@ func main() {
 		if user == nil {
 			// Return default settings if user not found
 			return c.JSON(fiber.Map{
-				"discord_webhook_url": "",
-				"message": "To enable Discord alerts, add your webhook URL below",
+				"discord_webhook_url":      "",
+				"notification_channels":    []models.NotificationChannel{},
+				"escalation_policies":      []models.EscalationPolicy{},
+				"ssl_expiry_thresholds":    services.SSLExpiryThresholds(nil),
+				"domain_expiry_thresholds": services.DomainExpiryThresholds(nil),
+				"message":                  "To enable alerts, add a Discord webhook or a notification channel below",
 			})
 		}
+		channels := models.RedactChannels(user.NotificationChannels)
+		policies := user.EscalationPolicies
+		if policies == nil {
+			policies = []models.EscalationPolicy{}
+		}
 		return c.JSON(fiber.Map{
-			"discord_webhook_url": user.DiscordWebhookURL,
+			"discord_webhook_url":      user.DiscordWebhookURL,
+			"notification_channels":    channels,
+			"escalation_policies":      policies,
+			"ssl_expiry_thresholds":    services.SSLExpiryThresholds(user),
+			"domain_expiry_thresholds": services.DomainExpiryThresholds(user),
 			"message": func() string {
-				if user.DiscordWebhookURL == "" {
-					return "To enable Discord alerts, add your webhook URL below"
+				if len(services.UserChannels(user)) == 0 {
+					return "To enable alerts, add a Discord webhook or a notification channel below"
 				}
-				return "Discord alerts are enabled"
+				return "Alerts are enabled"
 			}(),
 		})
 	})
@@ -434,40 +1022,229 @@ func main() {
 	// Update user settings (protected)
 	app.Put("/api/user/settings", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
 		userID := c.Locals("user_id").(string)
-		
+
 		var requestBody struct {
-			DiscordWebhookURL string `json:"discord_webhook_url"`
+			DiscordWebhookURL      *string                       `json:"discord_webhook_url"`
+			NotificationChannels   *[]models.NotificationChannel `json:"notification_channels"`
+			EscalationPolicies     *[]models.EscalationPolicy    `json:"escalation_policies"`
+			SSLExpiryThresholds    *[]int                        `json:"ssl_expiry_thresholds"`
+			DomainExpiryThresholds *[]int                        `json:"domain_expiry_thresholds"`
 		}
-		
+
 		if err := c.BodyParser(&requestBody); err != nil {
 			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
 		}
-		
+
 		// Get existing user or create new one
 		user, err := storageService.GetUser(userID)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch user"})
 		}
-		
+
 		if user == nil {
 			// Create new user
 			user = &models.User{
 				ID: userID,
 			}
 		}
-		
-		user.DiscordWebhookURL = requestBody.DiscordWebhookURL
-		
+
+		if requestBody.NotificationChannels != nil {
+			// Secrets sent back redacted are unchanged
+			models.KeepSecrets(*requestBody.NotificationChannels, user.NotificationChannels)
+			if validationErrors := utils.ValidateNotificationChannels(*requestBody.NotificationChannels); len(validationErrors) > 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"error":             "Validation failed",
+					"validation_errors": validationErrors,
+				})
+			}
+		}
+
+		if requestBody.SSLExpiryThresholds != nil {
+			if validationErrors := utils.ValidateExpiryThresholds("ssl_expiry_thresholds", *requestBody.SSLExpiryThresholds); len(validationErrors) > 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"error":             "Validation failed",
+					"validation_errors": validationErrors,
+				})
+			}
+		}
+
+		if requestBody.DomainExpiryThresholds != nil {
+			if validationErrors := utils.ValidateExpiryThresholds("domain_expiry_thresholds", *requestBody.DomainExpiryThresholds); len(validationErrors) > 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"error":             "Validation failed",
+					"validation_errors": validationErrors,
+				})
+			}
+		}
+
+		// Only overwrite the settings that were sent
+		if requestBody.DiscordWebhookURL != nil {
+			user.DiscordWebhookURL = *requestBody.DiscordWebhookURL
+		}
+		if requestBody.NotificationChannels != nil {
+			channels := *requestBody.NotificationChannels
+			for i := range channels {
+				if channels[i].ID == "" {
+					channels[i].ID = fmt.Sprintf("%d%d", time.Now().UnixNano(), i)
+				}
+				if channels[i].Name == "" {
+					channels[i].Name = channels[i].Type
+				}
+			}
+			user.NotificationChannels = channels
+		}
+		if requestBody.EscalationPolicies != nil {
+			policies := *requestBody.EscalationPolicies
+			for i := range policies {
+				if policies[i].ID == "" {
+					policies[i].ID = fmt.Sprintf("%d%d", time.Now().UnixNano(), i)
+				}
+				if policies[i].Name == "" {
+					policies[i].Name = fmt.Sprintf("Policy %d", i+1)
+				}
+			}
+			user.EscalationPolicies = policies
+		}
+		if requestBody.SSLExpiryThresholds != nil {
+			// An empty list restores the defaults
+			user.SSLExpiryThresholds = *requestBody.SSLExpiryThresholds
+		}
+		if requestBody.DomainExpiryThresholds != nil {
+			user.DomainExpiryThresholds = *requestBody.DomainExpiryThresholds
+		}
+
+		// Policies must only use channels that still exist
+		if requestBody.NotificationChannels != nil || requestBody.EscalationPolicies != nil {
+			channelIDs := make(map[string]bool)
+			for _, channel := range services.UserChannels(user) {
+				channelIDs[channel.ID] = true
+			}
+			if validationErrors := utils.ValidateEscalationPolicies(user.EscalationPolicies, channelIDs); len(validationErrors) > 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"error":             "Validation failed",
+					"validation_errors": validationErrors,
+				})
+			}
+		}
+
 		if err := storageService.SaveUser(*user); err != nil {
 			return c.Status(500).JSON(fiber.Map{"error": "Failed to save user settings"})
 		}
-		
+
 		return c.JSON(fiber.Map{
-			"success": true,
-			"message": "Settings updated successfully",
+			"success":                  true,
+			"message":                  "Settings updated successfully",
+			"notification_channels":    models.RedactChannels(user.NotificationChannels),
+			"escalation_policies":      user.EscalationPolicies,
+			"ssl_expiry_thresholds":    services.SSLExpiryThresholds(user),
+			"domain_expiry_thresholds": services.DomainExpiryThresholds(user),
 		})
 	})
 
+	// Send a test alert to a notification channel (protected)
+	app.Post("/api/user/settings/notifications/test", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		var channel models.NotificationChannel
+		if err := c.BodyParser(&channel); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		if user, err := storageService.GetUser(c.Locals("user_id").(string)); err == nil && user != nil {
+			// A saved channel is sent back with its secrets redacted
+			channels := []models.NotificationChannel{channel}
+			models.KeepSecrets(channels, user.NotificationChannels)
+			channel = channels[0]
+		}
+		if validationErrors := utils.ValidateNotificationChannels([]models.NotificationChannel{channel}); len(validationErrors) > 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"error":             "Validation failed",
+				"validation_errors": validationErrors,
+			})
+		}
+
+		notifier, err := notificationService.NotifierFor(channel)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		testAlert, err := services.SampleAlert(models.AlertUp).RenderTemplates(channel)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err := notifier.Notify(testAlert); err != nil {
+			return c.Status(502).JSON(fiber.Map{"error": "Failed to send test notification", "details": err.Error()})
+		}
+		return c.JSON(fiber.Map{"success": true})
+	})
+
+	// Render alert templates against sample data without sending (protected)
+	app.Post("/api/user/settings/notifications/preview", middleware.AuthMiddleware(), func(c *fiber.Ctx) error {
+		var requestBody struct {
+			TitleTemplate string `json:"title_template"`
+			BodyTemplate  string `json:"body_template"`
+			Event         string `json:"event"` // down (default) or up
+		}
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+		if validationErrors := utils.ValidateAlertTemplates("template", requestBody.TitleTemplate, requestBody.BodyTemplate); len(validationErrors) > 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"error":             "Validation failed",
+				"validation_errors": validationErrors,
+			})
+		}
+		event := requestBody.Event
+		if event == "" {
+			event = models.AlertDown
+		}
+		if event != models.AlertDown && event != models.AlertUp {
+			return c.Status(400).JSON(fiber.Map{"error": "event must be down or up"})
+		}
+
+		sample := services.SampleAlert(event)
+		alert, err := sample.RenderTemplates(models.NotificationChannel{
+			TitleTemplate: requestBody.TitleTemplate,
+			BodyTemplate:  requestBody.BodyTemplate,
+		})
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{
+			"title": alert.Title(),
+			"body":  alert.Message(),
+			"data":  sample.TemplateData(),
+		})
+	})
+
+	// === HEARTBEAT ENDPOINTS ===
+	// Jobs ping these to report in; the token in the URL authenticates them
+
+	// Record a heartbeat ping: ?status=start|success|fail&duration=<ms>&msg=<text>
+	// The signal can also be given as the last path segment, e.g. /api/push/:token/fail
+	handlePing := func(c *fiber.Ctx) error {
+		website, err := storageService.GetWebsiteByPushToken(c.Params("token"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"error": "Push monitor not found"})
+		}
+
+		signal := c.Params("signal", c.Query("status", models.PingSuccess))
+		switch signal {
+		case models.PingStart, models.PingSuccess, models.PingFail:
+		default:
+			return c.Status(400).JSON(fiber.Map{"error": "Status must be start, success or fail"})
+		}
+
+		status, err := heartbeatService.RecordPing(*website, signal, int64(c.QueryInt("duration", 0)), c.Query("msg"))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to record ping"})
+		}
+		if status != nil {
+			processResult(*website, *status)
+		}
+		return c.JSON(fiber.Map{"success": true})
+	}
+	app.Get("/api/push/:token", handlePing)
+	app.Post("/api/push/:token", handlePing)
+	app.Get("/api/push/:token/:signal", handlePing)
+	app.Post("/api/push/:token/:signal", handlePing)
+
 	// === PUBLIC STATUS PAGE ENDPOINTS ===
 	// These endpoints are public (no auth required) for status pages
 
@@ -485,29 +1262,57 @@ func main() {
 			IsUp         *bool   `json:"is_up"`
 			ResponseTime *int64  `json:"response_time_ms"`
 			LastChecked  *int64  `json:"last_checked"`
+			Maintenance  bool    `json:"maintenance"`
+			Degraded     bool    `json:"degraded"`
+			Status       string  `json:"status"` // up, down, degraded, maintenance or unknown
 			Uptime24h    float64 `json:"uptime_24h"`
 			Uptime7d     float64 `json:"uptime_7d"`
 		}
 
 		var publicStatuses []PublicWebsiteStatus
 		allUp := true
+		inMaintenance := 0
+		degraded := 0
 
 		for _, website := range websites {
+			// Never publish a push monitor's ping URL
+			if website.Type == models.MonitorPush {
+				website.URL = ""
+			}
+
 			// Get latest status
 			statuses, err := storageService.GetWebsiteStatuses(website.ID)
 			if err != nil || len(statuses) == 0 {
 				publicStatuses = append(publicStatuses, PublicWebsiteStatus{
 					ID:   website.ID,
 					Name: website.Name,
-					URL:  website.URL,
+					URL:    website.URL,
+					Status: "unknown",
 				})
 				allUp = false
 				continue
 			}
 
+			// Downtime during planned maintenance isn't an outage, and a
+			// degraded website is up but slower than its latency threshold
 			latest := statuses[0]
-			if !latest.IsUp {
+			status := "up"
+			switch {
+			case latest.Maintenance:
+				status = "maintenance"
+				inMaintenance++
+			case !latest.IsUp:
+				status = "down"
 				allUp = false
+			case latest.Degraded:
+				status = "degraded"
+				degraded++
+			}
+
+			// Push monitors are never requested, so they have no response time
+			responseTime := &latest.ResponseTime
+			if website.Type == models.MonitorPush {
+				responseTime = nil
 			}
 
 			// Calculate uptime percentages
@@ -519,17 +1324,29 @@ func main() {
 				Name:         website.Name,
 				URL:          website.URL,
 				IsUp:         &latest.IsUp,
-				ResponseTime: &latest.ResponseTime,
+				ResponseTime: responseTime,
 				LastChecked:  &latest.CheckedAt,
+				Maintenance:  latest.Maintenance,
+				Degraded:     latest.Degraded,
+				Status:       status,
 				Uptime24h:    uptime24h,
 				Uptime7d:     uptime7d,
 			})
 		}
 
+		// The status page shows "degraded" when anything is down; slow
+		// websites are only counted so that doesn't change
+		overall := "operational"
+		if !allUp {
+			overall = "degraded"
+		}
+
 		return c.JSON(fiber.Map{
 			"overall_status": map[string]interface{}{
 				"all_up":     allUp,
-				"status":     func() string { if allUp { return "operational" } else { return "degraded" } }(),
+				"maintenance": inMaintenance,
+				"degraded":   degraded,
+				"status":     overall,
 				"updated_at": time.Now().Unix(),
 			},
 			"services": publicStatuses,
@@ -569,8 +1386,26 @@ func main() {
 
 	fmt.Println("Website monitoring service started. Press Ctrl+C to exit.")
 
-	// Keep the program running
-	select {}
+	// Keep the program running until interrupted, then release the store
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	c.Stop()
+	if err := storageService.Close(); err != nil {
+		fmt.Printf("⚠️ Failed to close storage: %v\n", err)
+	}
+}
+
+// ackPage renders the minimal HTML page shown by one-click acknowledge
+// links, with a confirmation button posting to action if set
+func ackPage(title, message, action string) string {
+	form := ""
+	if action != "" {
+		form = fmt.Sprintf(`<form method="POST" action="%s"><button type="submit">Acknowledge</button></form>`, html.EscapeString(action))
+	}
+	return fmt.Sprintf(`<!DOCTYPE html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>PulseWatch</title></head>`+
+		`<body style="font-family:sans-serif;max-width:32em;margin:4em auto;text-align:center"><h1>%s</h1><p>%s</p>%s</body></html>`,
+		html.EscapeString(title), html.EscapeString(message), form)
 }
 
 // Keep-alive function to prevent Render free tier spin-down
@@ -605,6 +1440,10 @@ func calculateUptimePercentage(statuses []models.WebsiteStatus, minutes int) flo
 	totalCount := 0
 
 	for _, status := range statuses {
+		// Maintenance checks count neither for nor against uptime
+		if status.Maintenance {
+			continue
+		}
 		if status.CheckedAt >= cutoffTime {
 			totalCount++
 			if status.IsUp {
@@ -0,0 +1,13 @@
+package models
+
+// DomainInfo is the registration of an apex domain, shared by every
+// website under it
+type DomainInfo struct {
+	Domain    string `json:"domain" bson:"_id"`            // Apex domain, e.g. example.co.uk
+	Registrar string `json:"registrar" bson:"registrar"`   // Registrar name, if known
+	ExpiresAt int64  `json:"expires_at" bson:"expires_at"` // Unix timestamp the registration expires, 0 if unknown
+	DaysLeft  int    `json:"days_left" bson:"days_left"`   // Whole days until ExpiresAt
+	Source    string `json:"source" bson:"source"`         // "rdap" or "whois"
+	Error     string `json:"error" bson:"error"`           // Why the lookup failed, if it did
+	CheckedAt int64  `json:"checked_at" bson:"checked_at"` // Unix timestamp of the lookup
+}
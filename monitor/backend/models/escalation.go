@@ -0,0 +1,18 @@
+package models
+
+// EscalationPolicy decides who is notified while an incident stays
+// unacknowledged. Steps are notified in order once their delay has
+// passed; until the incident is acknowledged or resolved, the channels
+// of every step reached so far are reminded every RepeatMinutes.
+type EscalationPolicy struct {
+	ID            string           `json:"id" bson:"id"`                         // Unique within the user's policies
+	Name          string           `json:"name" bson:"name"`                     // Display name
+	Steps         []EscalationStep `json:"steps" bson:"steps"`                   // Ordered by AfterMinutes
+	RepeatMinutes int              `json:"repeat_minutes" bson:"repeat_minutes"` // Reminder interval while unacknowledged, 0 for no reminders
+}
+
+// EscalationStep notifies channels once an incident has been open for AfterMinutes
+type EscalationStep struct {
+	AfterMinutes int      `json:"after_minutes" bson:"after_minutes"` // Minutes after the incident opened, 0 for right away
+	ChannelIDs   []string `json:"channel_ids" bson:"channel_ids"`     // IDs of the user's notification channels
+}
@@ -0,0 +1,81 @@
+package models
+
+// Incident statuses
+const (
+	IncidentOpen     = "open"     // Website is confirmed down
+	IncidentResolved = "resolved" // Website recovered
+)
+
+// Incident timeline event types
+const (
+	EventOpened       = "opened"       // First confirmed down
+	EventFailure      = "failure"      // A new failure reason was seen while open
+	EventAcknowledged = "acknowledged" // Someone is handling the incident
+	EventNote         = "note"         // A note was added
+	EventRootCause    = "root_cause"   // The root cause was set or changed
+	EventEscalated    = "escalated"    // An escalation step was notified
+	EventFlapping     = "flapping"     // The website started flapping
+	EventStabilized   = "stabilized"   // The website stopped flapping
+	EventResolved     = "resolved"     // Website recovered
+)
+
+// MaxIncidentErrors caps the distinct errors recorded on one incident
+const MaxIncidentErrors = 20
+
+// Incident ties a website's outage together, from its first confirmed
+// down check until it recovers
+type Incident struct {
+	ID              string          `json:"id" bson:"_id"`                                                // Unique identifier
+	WebsiteID       string          `json:"website_id" bson:"website_id"`                                 // Website that went down
+	UserID          string          `json:"user_id" bson:"user_id"`                                       // Owner of the website
+	WebsiteName     string          `json:"website_name" bson:"website_name"`                             // Website name when the incident opened
+	Status          string          `json:"status" bson:"status"`                                         // IncidentOpen or IncidentResolved
+	StartedAt       int64           `json:"started_at" bson:"started_at"`                                 // Unix timestamp of the first confirmed down
+	ResolvedAt      int64           `json:"resolved_at,omitempty" bson:"resolved_at,omitempty"`           // Unix timestamp of the recovery
+	DurationSeconds int64           `json:"duration_seconds,omitempty" bson:"duration_seconds,omitempty"` // ResolvedAt - StartedAt
+	FailedChecks    int             `json:"failed_checks" bson:"failed_checks"`                           // Failed checks recorded while open
+	StatusCodes     []int           `json:"status_codes,omitempty" bson:"status_codes,omitempty"`         // Distinct failing HTTP status codes
+	LastError       string          `json:"last_error,omitempty" bson:"last_error,omitempty"`             // Most recent failure reason
+	Errors          []string        `json:"errors,omitempty" bson:"errors,omitempty"`                     // Distinct failure reasons, at most MaxIncidentErrors
+	Timeline        []IncidentEvent `json:"timeline" bson:"timeline"`                                     // What happened, oldest first
+
+	// Response
+	AcknowledgedAt int64          `json:"acknowledged_at,omitempty" bson:"acknowledged_at,omitempty"` // Unix timestamp of the acknowledgement, 0 if unacknowledged
+	AcknowledgedBy string         `json:"acknowledged_by,omitempty" bson:"acknowledged_by,omitempty"` // Who acknowledged: a user ID, or "alert link"
+	RootCause      string         `json:"root_cause,omitempty" bson:"root_cause,omitempty"`           // Free-text root cause
+	Notes          []IncidentNote `json:"notes,omitempty" bson:"notes,omitempty"`                     // Free-text notes, oldest first
+
+	// Escalation
+	EscalationPolicyID string `json:"escalation_policy_id,omitempty" bson:"escalation_policy_id,omitempty"` // Policy of the website when the incident opened
+	EscalationStep     int    `json:"escalation_step" bson:"escalation_step"`                               // Number of policy steps notified so far
+	LastNotifiedAt     int64  `json:"last_notified_at,omitempty" bson:"last_notified_at,omitempty"`         // Unix timestamp of the last escalation or reminder
+}
+
+// IncidentNote is a free-text note on an incident
+type IncidentNote struct {
+	At     int64  `json:"at" bson:"at"`         // Unix timestamp
+	Author string `json:"author" bson:"author"` // User ID of the author
+	Text   string `json:"text" bson:"text"`     // Note text
+}
+
+// Acknowledged reports whether someone acknowledged the incident
+func (i Incident) Acknowledged() bool {
+	return i.AcknowledgedAt != 0
+}
+
+// IncidentEvent is an entry in an incident's timeline
+type IncidentEvent struct {
+	Type    string `json:"type" bson:"type"`                           // One of the Event* constants
+	At      int64  `json:"at" bson:"at"`                               // Unix timestamp
+	Message string `json:"message,omitempty" bson:"message,omitempty"` // Human readable detail
+}
+
+// IncidentSummary aggregates incidents over a period
+type IncidentSummary struct {
+	Since                int64 `json:"since"`                  // Unix timestamp the summary starts at
+	Total                int   `json:"total"`                  // Incidents started since Since
+	Open                 int   `json:"open"`                   // Of which still open
+	Resolved             int   `json:"resolved"`               // Of which resolved
+	MTTRSeconds          int64 `json:"mttr_seconds"`           // Mean time to recovery of the resolved incidents
+	TotalDowntimeSeconds int64 `json:"total_downtime_seconds"` // Summed duration of the resolved incidents
+}
@@ -0,0 +1,24 @@
+package models
+
+// MaintenanceWindow is a period during which a user's websites are still
+// checked but alerts are suppressed and results are flagged as maintenance.
+// A window is either one-off (StartsAt to EndsAt) or recurring (Schedule
+// plus DurationMinutes).
+type MaintenanceWindow struct {
+	ID         string   `json:"id" bson:"_id"`                                      // Unique identifier
+	UserID     string   `json:"user_id" bson:"user_id"`                             // Owner of the window
+	Name       string   `json:"name" bson:"name"`                                   // Display name, e.g. "Tuesday deploy"
+	WebsiteIDs []string `json:"website_ids,omitempty" bson:"website_ids,omitempty"` // Websites covered
+	Tags       []string `json:"tags,omitempty" bson:"tags,omitempty"`               // Websites with any of these tags are covered too
+
+	// One-off window
+	StartsAt int64 `json:"starts_at,omitempty" bson:"starts_at,omitempty"` // Unix timestamp
+	EndsAt   int64 `json:"ends_at,omitempty" bson:"ends_at,omitempty"`     // Unix timestamp
+
+	// Recurring window
+	Schedule        string `json:"schedule,omitempty" bson:"schedule,omitempty"`                 // 5-field cron expression or RRULE for the start of each occurrence
+	DurationMinutes int    `json:"duration_minutes,omitempty" bson:"duration_minutes,omitempty"` // Length of each occurrence
+	Timezone        string `json:"timezone,omitempty" bson:"timezone,omitempty"`                 // IANA time zone of Schedule (default UTC)
+
+	CreatedAt int64 `json:"created_at" bson:"created_at"` // Unix timestamp
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"net/url"
+	"sort"
+)
+
+// Notification channel types
+const (
+	ChannelDiscord  = "discord"
+	ChannelSlack    = "slack"
+	ChannelTeams    = "teams"
+	ChannelTelegram = "telegram"
+	ChannelEmail    = "email"
+	ChannelNtfy     = "ntfy"
+	ChannelGotify   = "gotify"
+	ChannelWebhook  = "webhook"
+)
+
+// RedactedSecret stands in for channel secrets in API responses. A
+// redacted value sent back keeps the stored secret.
+const RedactedSecret = "********"
+
+// NotificationChannel is a destination a user's alerts are delivered to
+type NotificationChannel struct {
+	ID       string            `json:"id" bson:"id"`                                   // Unique within the user's channels
+	Type     string            `json:"type" bson:"type"`                               // One of the Channel* constants
+	Name     string            `json:"name" bson:"name"`                               // Display name
+	Disabled bool              `json:"disabled" bson:"disabled"`                       // Skip this channel when alerting
+	URL      string            `json:"url,omitempty" bson:"url,omitempty"`             // Webhook URL, or server URL for ntfy/Gotify/Telegram
+	Token    string            `json:"token,omitempty" bson:"token,omitempty"`         // Telegram bot token, Gotify app token or ntfy access token
+	ChatID   string            `json:"chat_id,omitempty" bson:"chat_id,omitempty"`     // Telegram chat ID
+	Topic    string            `json:"topic,omitempty" bson:"topic,omitempty"`         // ntfy topic
+	Headers  map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`     // Extra headers for generic webhooks
+	SMTPHost string            `json:"smtp_host,omitempty" bson:"smtp_host,omitempty"` // SMTP server host
+	SMTPPort int               `json:"smtp_port,omitempty" bson:"smtp_port,omitempty"` // SMTP server port (default 587)
+	Username string            `json:"username,omitempty" bson:"username,omitempty"`   // SMTP username
+	Password string            `json:"password,omitempty" bson:"password,omitempty"`   // SMTP password
+	From     string            `json:"from,omitempty" bson:"from,omitempty"`           // Sender address
+	To       []string          `json:"to,omitempty" bson:"to,omitempty"`               // Recipient addresses
+
+	// Optional Go text/template overrides of the alert title and body
+	TitleTemplate string `json:"title_template,omitempty" bson:"title_template,omitempty"`
+	BodyTemplate  string `json:"body_template,omitempty" bson:"body_template,omitempty"`
+}
+
+// Redacted returns the channel with its token, password and header values
+// replaced by RedactedSecret, as well as the path and query of webhook
+// URLs, which carry their credentials
+func (c NotificationChannel) Redacted() NotificationChannel {
+	switch c.Type {
+	case ChannelDiscord, ChannelSlack, ChannelTeams, ChannelWebhook:
+		c.URL = redactURL(c.URL)
+	}
+	if c.Token != "" {
+		c.Token = RedactedSecret
+	}
+	if c.Password != "" {
+		c.Password = RedactedSecret
+	}
+	if len(c.Headers) > 0 {
+		headers := make(map[string]string, len(c.Headers))
+		for name := range c.Headers {
+			headers[name] = RedactedSecret
+		}
+		c.Headers = headers
+	}
+	return c
+}
+
+// redactURL keeps only the scheme and host of a webhook URL
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return RedactedSecret
+	}
+	return u.Scheme + "://" + u.Host + "/" + RedactedSecret
+}
+
+// RedactedFields returns the names of the channel's fields still holding
+// a redacted secret
+func (c NotificationChannel) RedactedFields() []string {
+	var fields []string
+	if c.URL != "" && c.URL == redactURL(c.URL) {
+		fields = append(fields, "url")
+	}
+	if c.Token == RedactedSecret {
+		fields = append(fields, "token")
+	}
+	if c.Password == RedactedSecret {
+		fields = append(fields, "password")
+	}
+	var headers []string
+	for name, value := range c.Headers {
+		if value == RedactedSecret {
+			headers = append(headers, "headers."+name)
+		}
+	}
+	sort.Strings(headers)
+	return append(fields, headers...)
+}
+
+// RedactChannels returns channels with their secrets redacted
+func RedactChannels(channels []NotificationChannel) []NotificationChannel {
+	redacted := make([]NotificationChannel, len(channels))
+	for i, channel := range channels {
+		redacted[i] = channel.Redacted()
+	}
+	return redacted
+}
+
+// KeepSecrets replaces redacted secrets in channels with those of the
+// stored channel with the same ID. They are only kept while the channel's
+// destination (its URL and SMTP host) is unchanged, so stored secrets
+// can't be sent elsewhere without being entered again.
+func KeepSecrets(channels, stored []NotificationChannel) {
+	byID := make(map[string]NotificationChannel, len(stored))
+	for _, channel := range stored {
+		byID[channel.ID] = channel
+	}
+	for i := range channels {
+		previous, ok := byID[channels[i].ID]
+		if channels[i].ID == "" || !ok || channels[i].Type != previous.Type {
+			continue
+		}
+		if channels[i].URL != previous.URL && channels[i].URL == previous.Redacted().URL {
+			channels[i].URL = previous.URL
+		}
+		if channels[i].URL != previous.URL || channels[i].SMTPHost != previous.SMTPHost {
+			continue
+		}
+		if channels[i].Token == RedactedSecret {
+			channels[i].Token = previous.Token
+		}
+		if channels[i].Password == RedactedSecret {
+			channels[i].Password = previous.Password
+		}
+		for name, value := range channels[i].Headers {
+			if value == RedactedSecret {
+				channels[i].Headers[name] = previous.Headers[name]
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedacted(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel NotificationChannel
+		want    NotificationChannel
+	}{
+		{
+			name:    "webhook URLs keep only their host",
+			channel: NotificationChannel{Type: ChannelDiscord, URL: "https://discord.com/api/webhooks/1/abc?wait=true"},
+			want:    NotificationChannel{Type: ChannelDiscord, URL: "https://discord.com/" + RedactedSecret},
+		},
+		{
+			name:    "generic webhooks",
+			channel: NotificationChannel{Type: ChannelWebhook, URL: "https://hooks.example.com/x", Headers: map[string]string{"Authorization": "Bearer t"}},
+			want:    NotificationChannel{Type: ChannelWebhook, URL: "https://hooks.example.com/" + RedactedSecret, Headers: map[string]string{"Authorization": RedactedSecret}},
+		},
+		{
+			name:    "server URLs are kept",
+			channel: NotificationChannel{Type: ChannelGotify, URL: "https://gotify.example.com/push", Token: "t"},
+			want:    NotificationChannel{Type: ChannelGotify, URL: "https://gotify.example.com/push", Token: RedactedSecret},
+		},
+		{
+			name:    "email password",
+			channel: NotificationChannel{Type: ChannelEmail, SMTPHost: "smtp.example.com", Password: "p"},
+			want:    NotificationChannel{Type: ChannelEmail, SMTPHost: "smtp.example.com", Password: RedactedSecret},
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.channel.Redacted(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Redacted() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestKeepSecrets(t *testing.T) {
+	slack := NotificationChannel{ID: "s", Type: ChannelSlack, URL: "https://hooks.slack.com/services/T/B/secret"}
+	telegram := NotificationChannel{ID: "t", Type: ChannelTelegram, URL: "https://api.telegram.org", Token: "123:abc", ChatID: "42"}
+	email := NotificationChannel{ID: "e", Type: ChannelEmail, SMTPHost: "smtp.example.com", Username: "u", Password: "p"}
+	stored := []NotificationChannel{slack, telegram, email}
+
+	tests := []struct {
+		name    string
+		channel NotificationChannel
+		want    NotificationChannel
+	}{
+		{
+			name:    "unchanged channels keep their secrets",
+			channel: telegram.Redacted(),
+			want:    telegram,
+		},
+		{
+			name:    "redacted webhook URL",
+			channel: slack.Redacted(),
+			want:    slack,
+		},
+		{
+			name:    "new webhook URL",
+			channel: NotificationChannel{ID: "s", Type: ChannelSlack, URL: "https://hooks.slack.com/services/T/B/other"},
+			want:    NotificationChannel{ID: "s", Type: ChannelSlack, URL: "https://hooks.slack.com/services/T/B/other"},
+		},
+		{
+			name:    "changed Telegram API URL",
+			channel: NotificationChannel{ID: "t", Type: ChannelTelegram, URL: "https://attacker.example.com", Token: RedactedSecret, ChatID: "42"},
+			want:    NotificationChannel{ID: "t", Type: ChannelTelegram, URL: "https://attacker.example.com", Token: RedactedSecret, ChatID: "42"},
+		},
+		{
+			name:    "changed SMTP host",
+			channel: NotificationChannel{ID: "e", Type: ChannelEmail, SMTPHost: "smtp.attacker.example.com", Username: "u", Password: RedactedSecret},
+			want:    NotificationChannel{ID: "e", Type: ChannelEmail, SMTPHost: "smtp.attacker.example.com", Username: "u", Password: RedactedSecret},
+		},
+		{
+			name:    "changed type",
+			channel: NotificationChannel{ID: "s", Type: ChannelDiscord, URL: slack.Redacted().URL},
+			want:    NotificationChannel{ID: "s", Type: ChannelDiscord, URL: slack.Redacted().URL},
+		},
+		{
+			name:    "new channel",
+			channel: NotificationChannel{ID: "n", Type: ChannelGotify, URL: "https://gotify.example.com", Token: RedactedSecret},
+			want:    NotificationChannel{ID: "n", Type: ChannelGotify, URL: "https://gotify.example.com", Token: RedactedSecret},
+		},
+	}
+	for _, tt := range tests {
+		channels := []NotificationChannel{tt.channel}
+		KeepSecrets(channels, stored)
+		if !reflect.DeepEqual(channels[0], tt.want) {
+			t.Errorf("%s: KeepSecrets() = %+v, want %+v", tt.name, channels[0], tt.want)
+		}
+	}
+}
+
+func TestRedactedFields(t *testing.T) {
+	channel := NotificationChannel{
+		Type:    ChannelWebhook,
+		URL:     "https://hooks.example.com/" + RedactedSecret,
+		Headers: map[string]string{"X-Token": RedactedSecret, "Authorization": RedactedSecret, "Accept": "application/json"},
+	}
+	want := []string{"url", "headers.Authorization", "headers.X-Token"}
+	if got := channel.RedactedFields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactedFields() = %q, want %q", got, want)
+	}
+	if got := (NotificationChannel{Type: ChannelSlack, URL: "https://hooks.slack.com/services/x"}).RedactedFields(); got != nil {
+		t.Errorf("RedactedFields() = %q for a complete channel", got)
+	}
+}
@@ -1,5 +1,23 @@
 package models
 
+// Reasons a certificate chain failed verification
+const (
+	SSLExpired             = "expired"              // Leaf certificate has expired
+	SSLNotYetValid         = "not_yet_valid"        // Leaf certificate isn't valid yet
+	SSLExpiredIntermediate = "expired_intermediate" // An intermediate in the served chain has expired
+	SSLHostnameMismatch    = "hostname_mismatch"    // Leaf certificate doesn't cover the hostname
+	SSLSelfSigned          = "self_signed"          // Leaf certificate signed itself
+	SSLUnknownAuthority    = "unknown_authority"    // Chain doesn't lead to a trusted root
+	SSLInvalid             = "invalid"              // Any other verification failure
+)
+
+// OCSP revocation statuses
+const (
+	OCSPGood    = "good"    // Responder vouches for the certificate
+	OCSPRevoked = "revoked" // Certificate was revoked by its issuer
+	OCSPUnknown = "unknown" // Responder doesn't know the certificate
+)
+
 type SSLInfo struct {
 	WebsiteID string `json:"website_id" bson:"website_id"`
 	Host      string `json:"host" bson:"host"`
@@ -9,4 +27,70 @@ type SSLInfo struct {
 	Error     string `json:"error" bson:"error"`
 	CheckedAt int64  `json:"checked_at" bson:"checked_at"`
 	DaysLeft  int    `json:"days_left" bson:"days_left"`
+
+	// Leaf certificate details
+	Subject            string   `json:"subject" bson:"subject"`                         // Common name, or the full subject without one
+	SANs               []string `json:"sans" bson:"sans"`                               // DNS names and IP addresses the certificate covers
+	KeyType            string   `json:"key_type" bson:"key_type"`                       // RSA, ECDSA or Ed25519
+	KeySize            int      `json:"key_size" bson:"key_size"`                       // Key size in bits
+	SignatureAlgorithm string   `json:"signature_algorithm" bson:"signature_algorithm"` // e.g. SHA256-RSA
+	Fingerprint        string   `json:"fingerprint" bson:"fingerprint"`                 // SHA-256 of the certificate, hex encoded
+
+	// Connection and verification
+	TLSVersion         string            `json:"tls_version" bson:"tls_version"`                                   // Negotiated version, e.g. TLS 1.3
+	CipherSuite        string            `json:"cipher_suite" bson:"cipher_suite"`                                 // Negotiated cipher suite
+	Verified           bool              `json:"verified" bson:"verified"`                                         // Chain verified against the system roots for Host
+	VerificationReason string            `json:"verification_reason" bson:"verification_reason"`                   // One of the SSL* reasons if verification failed
+	VerificationError  string            `json:"verification_error,omitempty" bson:"verification_error,omitempty"` // Why verification failed
+	Chain              []CertificateInfo `json:"chain" bson:"chain"`                                               // Certificates as served, leaf first
+
+	// Revocation (OCSP)
+	OCSPStatus           string `json:"ocsp_status" bson:"ocsp_status"`                                           // One of the OCSP* statuses, empty if it couldn't be checked
+	OCSPSource           string `json:"ocsp_source" bson:"ocsp_source"`                                           // "stapled" or "responder"
+	OCSPThisUpdate       int64  `json:"ocsp_this_update" bson:"ocsp_this_update"`                                 // Unix timestamp the status was known to be correct
+	OCSPNextUpdate       int64  `json:"ocsp_next_update" bson:"ocsp_next_update"`                                 // Unix timestamp newer status will be available, 0 if unspecified
+	OCSPStale            bool   `json:"ocsp_stale" bson:"ocsp_stale"`                                             // Response is past its next update
+	OCSPRevokedAt        int64  `json:"ocsp_revoked_at,omitempty" bson:"ocsp_revoked_at,omitempty"`               // Unix timestamp of the revocation
+	OCSPRevocationReason string `json:"ocsp_revocation_reason,omitempty" bson:"ocsp_revocation_reason,omitempty"` // e.g. key_compromise
+	OCSPError            string `json:"ocsp_error,omitempty" bson:"ocsp_error,omitempty"`                         // Why the status couldn't be checked
+}
+
+// CertificateInfo describes one certificate of a served chain
+type CertificateInfo struct {
+	Subject            string   `json:"subject" bson:"subject"`                         // Common name, or the full subject without one
+	Issuer             string   `json:"issuer" bson:"issuer"`                           // Issuer common name, or the full issuer without one
+	SANs               []string `json:"sans,omitempty" bson:"sans,omitempty"`           // DNS names and IP addresses covered
+	ValidFrom          int64    `json:"valid_from" bson:"valid_from"`                   // Unix timestamp
+	ValidTo            int64    `json:"valid_to" bson:"valid_to"`                       // Unix timestamp
+	IsCA               bool     `json:"is_ca" bson:"is_ca"`                             // Whether it may sign other certificates
+	KeyType            string   `json:"key_type" bson:"key_type"`                       // RSA, ECDSA or Ed25519
+	KeySize            int      `json:"key_size" bson:"key_size"`                       // Key size in bits
+	SignatureAlgorithm string   `json:"signature_algorithm" bson:"signature_algorithm"` // e.g. SHA256-RSA
+	SerialNumber       string   `json:"serial_number" bson:"serial_number"`             // Hex encoded
+	Fingerprint        string   `json:"fingerprint" bson:"fingerprint"`                 // SHA-256 of the certificate, hex encoded
+}
+
+// CertificateRecord is a certificate a website has served, kept so
+// rotations can be reviewed after the fact
+type CertificateRecord struct {
+	ID                 string   `json:"id" bson:"_id"`                                  // WebsiteID:Fingerprint
+	WebsiteID          string   `json:"website_id" bson:"website_id"`                   // ID of the website that served it
+	Host               string   `json:"host" bson:"host"`                               // Host it was served on
+	Fingerprint        string   `json:"fingerprint" bson:"fingerprint"`                 // SHA-256 of the certificate, hex encoded
+	Subject            string   `json:"subject" bson:"subject"`                         // Common name, or the full subject without one
+	Issuer             string   `json:"issuer" bson:"issuer"`                           // Issuer common name, or the full issuer without one
+	SANs               []string `json:"sans" bson:"sans"`                               // DNS names and IP addresses covered
+	KeyType            string   `json:"key_type" bson:"key_type"`                       // RSA, ECDSA or Ed25519
+	KeySize            int      `json:"key_size" bson:"key_size"`                       // Key size in bits
+	SignatureAlgorithm string   `json:"signature_algorithm" bson:"signature_algorithm"` // e.g. SHA256-RSA
+	SerialNumber       string   `json:"serial_number" bson:"serial_number"`             // Hex encoded
+	ValidFrom          int64    `json:"valid_from" bson:"valid_from"`                   // Unix timestamp
+	ValidTo            int64    `json:"valid_to" bson:"valid_to"`                       // Unix timestamp
+	FirstSeen          int64    `json:"first_seen" bson:"first_seen"`                   // Unix timestamp it was first served
+	LastSeen           int64    `json:"last_seen" bson:"last_seen"`                     // Unix timestamp it was last served
+
+	// Replacement of the previous certificate, empty for the first one seen
+	PreviousFingerprint string   `json:"previous_fingerprint,omitempty" bson:"previous_fingerprint,omitempty"` // Certificate it replaced
+	Changes             []string `json:"changes,omitempty" bson:"changes,omitempty"`                           // What changed, e.g. the issuer or key
+	Unexpected          bool     `json:"unexpected" bson:"unexpected"`                                         // Replaced outside the renewal window or by a different issuer or key type
 }
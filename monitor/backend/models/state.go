@@ -0,0 +1,48 @@
+package models
+
+// Alert types; the up/down ones are recorded in MonitorState.LastAlert
+const (
+	AlertDown           = "down"
+	AlertUp             = "up"
+	AlertFlapping       = "flapping"        // Started alternating between up and down
+	AlertStabilized     = "stabilized"      // Stopped flapping; IsUp is the settled state
+	AlertDegraded       = "degraded"        // Responses became slower than the latency threshold
+	AlertRestored       = "restored"        // Responses are back under the latency threshold
+	AlertSSLExpiring    = "ssl_expiring"    // Certificate crossed one of the owner's expiry thresholds
+	AlertSSLError       = "ssl_error"       // Certificate check started failing, e.g. the handshake
+	AlertSSLChanged     = "ssl_changed"     // Certificate was replaced unexpectedly
+	AlertSSLRevoked     = "ssl_revoked"     // OCSP reports the certificate revoked, or doesn't know it
+	AlertSSLUntrusted   = "ssl_untrusted"   // Certificate chain stopped verifying, e.g. self-signed or the wrong hostname
+	AlertDomainExpiring = "domain_expiring" // Domain registration crossed one of the owner's expiry thresholds
+)
+
+// MonitorState is the persisted alerting state of a website, shared by
+// every instance so alerts are deduplicated across restarts
+type MonitorState struct {
+	WebsiteID              string  `json:"website_id" bson:"_id"`                                                        // ID of the website this state belongs to, or a DomainStateID
+	IsUp                   bool    `json:"is_up" bson:"is_up"`                                                           // Confirmed state, see FailureThreshold/RecoveryThreshold
+	LastChangedAt          int64   `json:"last_changed_at" bson:"last_changed_at"`                                       // Unix timestamp of the last up/down transition
+	LastCheckedAt          int64   `json:"last_checked_at" bson:"last_checked_at"`                                       // Unix timestamp of the last recorded check
+	ConsecutiveFailures    int     `json:"consecutive_failures" bson:"consecutive_failures"`                             // Failed checks in a row
+	ConsecutiveSuccesses   int     `json:"consecutive_successes" bson:"consecutive_successes"`                           // Successful checks in a row
+	LastPingAt             int64   `json:"last_ping_at,omitempty" bson:"last_ping_at,omitempty"`                         // Unix timestamp of the last heartbeat ping (push monitors)
+	LastStartAtMs          int64   `json:"last_start_at_ms,omitempty" bson:"last_start_at_ms,omitempty"`                 // Unix time in milliseconds of the last start signal (push monitors)
+	MissedDeadline         int64   `json:"missed_deadline,omitempty" bson:"missed_deadline,omitempty"`                   // Unix timestamp of the last ping deadline recorded as missed (push monitors)
+	RecentResults          []bool  `json:"recent_results,omitempty" bson:"recent_results,omitempty"`                     // Latest raw check results, oldest first, for flap detection
+	Flapping               bool    `json:"flapping" bson:"flapping"`                                                     // Alerts are damped while the website flaps
+	FlappingSince          int64   `json:"flapping_since,omitempty" bson:"flapping_since,omitempty"`                     // Unix timestamp flapping started
+	Degraded               bool    `json:"degraded" bson:"degraded"`                                                     // Responses are slower than the latency threshold
+	DegradedSince          int64   `json:"degraded_since,omitempty" bson:"degraded_since,omitempty"`                     // Unix timestamp the website became degraded
+	ConsecutiveSlow        int     `json:"consecutive_slow" bson:"consecutive_slow"`                                     // Slow successful checks in a row
+	RecentResponseTimes    []int64 `json:"recent_response_times,omitempty" bson:"recent_response_times,omitempty"`       // Latest successful response times in ms, oldest first
+	SSLValidTo             int64   `json:"ssl_valid_to,omitempty" bson:"ssl_valid_to,omitempty"`                         // Expiry of the certificate SSL alerts were last sent for
+	SSLAlertedThreshold    int     `json:"ssl_alerted_threshold,omitempty" bson:"ssl_alerted_threshold,omitempty"`       // Lowest expiry threshold (days) alerted for that certificate
+	SSLError               string  `json:"ssl_error,omitempty" bson:"ssl_error,omitempty"`                               // Certificate check error that was alerted, until it clears
+	SSLOCSPStatus          string  `json:"ssl_ocsp_status,omitempty" bson:"ssl_ocsp_status,omitempty"`                   // Revoked or unknown OCSP status alerted for that certificate
+	SSLUntrusted           string  `json:"ssl_untrusted,omitempty" bson:"ssl_untrusted,omitempty"`                       // Verification failure that was alerted, until the chain verifies again
+	DomainExpiresAt        int64   `json:"domain_expires_at,omitempty" bson:"domain_expires_at,omitempty"`               // Registration expiry domain alerts were last sent for
+	DomainAlertedThreshold int     `json:"domain_alerted_threshold,omitempty" bson:"domain_alerted_threshold,omitempty"` // Lowest expiry threshold (days) alerted for that registration
+	LastAlert              string  `json:"last_alert" bson:"last_alert"`                                                 // Last alert sent (AlertDown/AlertUp), empty if none
+	LastAlertAt            int64   `json:"last_alert_at" bson:"last_alert_at"`                                           // Unix timestamp of the last alert
+	Version                int64   `json:"-" bson:"version"`                                                             // Optimistic locking counter
+}
@@ -2,9 +2,13 @@ package models
 
 // User represents user settings and preferences
 type User struct {
-	ID                string `json:"id" bson:"_id,omitempty"`                          // Supabase user ID
-	Email             string `json:"email" bson:"email"`                               // User email from Supabase
-	DiscordWebhookURL string `json:"discord_webhook_url" bson:"discord_webhook_url"`   // User's Discord webhook URL
-	CreatedAt         int64  `json:"created_at" bson:"created_at"`                     // Unix timestamp
-	UpdatedAt         int64  `json:"updated_at" bson:"updated_at"`                     // Unix timestamp
-}
\ No newline at end of file
+	ID                     string                `json:"id" bson:"_id,omitempty"`                                  // Supabase user ID
+	Email                  string                `json:"email" bson:"email"`                                       // User email from Supabase
+	DiscordWebhookURL      string                `json:"discord_webhook_url" bson:"discord_webhook_url"`           // User's Discord webhook URL
+	NotificationChannels   []NotificationChannel `json:"notification_channels" bson:"notification_channels"`       // Additional alert destinations
+	EscalationPolicies     []EscalationPolicy    `json:"escalation_policies" bson:"escalation_policies"`           // Policies websites can attach via EscalationPolicyID
+	SSLExpiryThresholds    []int                 `json:"ssl_expiry_thresholds" bson:"ssl_expiry_thresholds"`       // Days before a certificate expires to alert at (default 30, 14, 7 and 1)
+	DomainExpiryThresholds []int                 `json:"domain_expiry_thresholds" bson:"domain_expiry_thresholds"` // Days before a domain registration expires to alert at (default 30, 14, 7 and 1)
+	CreatedAt              int64                 `json:"created_at" bson:"created_at"`                             // Unix timestamp
+	UpdatedAt              int64                 `json:"updated_at" bson:"updated_at"`                             // Unix timestamp
+}
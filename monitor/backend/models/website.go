@@ -6,22 +6,123 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 ) // Import for ObjectID
 
+// Monitor types
+const (
+	MonitorHTTP = "http" // HTTP(S) request to URL
+	MonitorTCP  = "tcp"  // TCP connect to URL as host:port
+	MonitorDNS  = "dns"  // DNS lookup of URL as a hostname
+	MonitorTLS  = "tls"  // TLS handshake with URL as host:port (default port 443)
+	MonitorPush = "push" // Heartbeat: expects a ping to /api/push/:token every Interval
+)
+
+// Protocols a TLS monitor speaks before its TLS handshake
+const (
+	TLSDirect   = "tls"      // TLS from the first byte (default port 443)
+	TLSSMTP     = "smtp"     // SMTP STARTTLS (default port 25)
+	TLSIMAP     = "imap"     // IMAP STARTTLS (default port 143)
+	TLSPOP3     = "pop3"     // POP3 STLS (default port 110)
+	TLSLDAP     = "ldap"     // LDAP StartTLS extended operation (default port 389)
+	TLSPostgres = "postgres" // PostgreSQL SSLRequest (default port 5432)
+)
+
+// Heartbeat signals accepted by push monitors
+const (
+	PingStart   = "start"   // Job started; the next success measures its duration
+	PingSuccess = "success" // Job finished successfully
+	PingFail    = "fail"    // Job reported a failure
+)
+
 // Website represents a website we want to monitor
 type Website struct {
-	ID       string `json:"id" bson:"_id,omitempty"`  // Unique identifier, maps to MongoDB's _id
-	Name     string `json:"name" bson:"name"`         // Display name
-	URL      string `json:"url" bson:"url"`           // Full URL to check
-	Interval int    `json:"interval" bson:"interval"` // Check interval in seconds
-	UserID   string `json:"user_id" bson:"user_id"`   // Supabase user ID who owns this website
+	ID       string   `json:"id" bson:"_id,omitempty"`              // Unique identifier, maps to MongoDB's _id
+	Name     string   `json:"name" bson:"name"`                     // Display name
+	URL      string   `json:"url" bson:"url"`                       // Full URL to check, or host[:port] for non-HTTP monitors
+	Interval int      `json:"interval" bson:"interval"`             // Check interval in seconds
+	UserID   string   `json:"user_id" bson:"user_id"`               // Supabase user ID who owns this website
+	Type     string   `json:"type" bson:"type"`                     // Monitor type, one of the Monitor* constants (default http)
+	Tags     []string `json:"tags,omitempty" bson:"tags,omitempty"` // Labels used to group websites, e.g. by maintenance window
+
+	// Heartbeat definition (type push)
+	PushToken   string `json:"push_token,omitempty" bson:"push_token,omitempty"`     // Secret token in the ping URL
+	GracePeriod int    `json:"grace_period,omitempty" bson:"grace_period,omitempty"` // Seconds a ping may be late before the monitor is down (default 60)
+
+	// TLS check definition (type tls)
+	TLSProtocol string `json:"tls_protocol,omitempty" bson:"tls_protocol,omitempty"` // One of the TLS* protocols, upgraded with STARTTLS or its equivalent (default tls, or the target's scheme)
+
+	// DNS check definition (type dns)
+	DNSRecordType    string `json:"dns_record_type,omitempty" bson:"dns_record_type,omitempty"`       // A, AAAA, CNAME, MX, NS or TXT (default A)
+	DNSExpectedValue string `json:"dns_expected_value,omitempty" bson:"dns_expected_value,omitempty"` // Record value that must be present, if set
+	DNSResolver      string `json:"dns_resolver,omitempty" bson:"dns_resolver,omitempty"`             // Resolver to query as host:port (default system resolver)
+
+	// HTTP check definition; zero values use the defaults
+	Method              string            `json:"method,omitempty" bson:"method,omitempty"`                               // HTTP method (default GET)
+	Headers             map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`                             // Extra request headers
+	Body                string            `json:"body,omitempty" bson:"body,omitempty"`                                   // Request body
+	Timeout             int               `json:"timeout,omitempty" bson:"timeout,omitempty"`                             // Check timeout in seconds (default 30)
+	AcceptedStatusCodes []string          `json:"accepted_status_codes,omitempty" bson:"accepted_status_codes,omitempty"` // Codes or ranges counted as up, e.g. "200-299", "418" (default 200-399, 403, 429)
+	FollowRedirects     *bool             `json:"follow_redirects,omitempty" bson:"follow_redirects,omitempty"`           // Whether to follow redirects (default true)
+	MaxRedirects        int               `json:"max_redirects,omitempty" bson:"max_redirects,omitempty"`                 // Redirects to follow before failing (default 10)
+	Assertions          []Assertion       `json:"assertions,omitempty" bson:"assertions,omitempty"`                       // Response checks that must all pass for the site to be up
+
+	// Confirmation settings; zero values use the defaults
+	FailureThreshold     int  `json:"failure_threshold" bson:"failure_threshold"`                               // Consecutive failed checks before the site is considered down (default 1)
+	RecoveryThreshold    int  `json:"recovery_threshold" bson:"recovery_threshold"`                             // Consecutive successful checks before it is considered up again (default 1)
+	Retries              *int `json:"retries,omitempty" bson:"retries,omitempty"`                               // Immediate retries after a failed request (default 1)
+	FlapThreshold        int  `json:"flap_threshold,omitempty" bson:"flap_threshold,omitempty"`                 // Percent of state changes across recent checks that counts as flapping (default 30)
+	DisableFlapDetection bool `json:"disable_flap_detection,omitempty" bson:"disable_flap_detection,omitempty"` // Alert on every confirmed transition even when flapping
+
+	// Latency thresholds; a slow but successful check is degraded, not down
+	DegradedThresholdMs int `json:"degraded_threshold_ms,omitempty" bson:"degraded_threshold_ms,omitempty"` // Response time above which a check is slow (0 disables)
+	DegradedChecks      int `json:"degraded_checks,omitempty" bson:"degraded_checks,omitempty"`             // Consecutive slow checks before the site is degraded (default 3)
+	DegradedWindow      int `json:"degraded_window,omitempty" bson:"degraded_window,omitempty"`             // Recent checks whose p95 is compared to the threshold (default 20)
+
+	// Alerting
+	EscalationPolicyID string `json:"escalation_policy_id,omitempty" bson:"escalation_policy_id,omitempty"` // ID of one of the owner's escalation policies; without one, alerts go to every channel
+
+	CreatedAt int64 `json:"created_at,omitempty" bson:"created_at,omitempty"` // Unix timestamp
 }
 
 // WebsiteStatus represents the result of checking a website
 type WebsiteStatus struct {
-	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`        // MongoDB generates unique ID for each status entry
-	WebsiteID     string             `json:"website_id" bson:"website_id"`             // ID of the website this status belongs to
-	IsUp          bool               `json:"is_up" bson:"is_up"`                       // Whether the site is up
-	StatusCode    int                `json:"status_code" bson:"status_code"`           // HTTP status code
-	ResponseTime  int64              `json:"response_time_ms" bson:"response_time_ms"` // Response time in milliseconds
-	CheckedAt     int64              `json:"checked_at" bson:"checked_at"`             // Unix timestamp of check
-	CheckedAtDate time.Time          `json:"checked_at_date" bson:"checked_at_date,omitempty"`
+	ID              primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`        // MongoDB generates unique ID for each status entry
+	WebsiteID       string             `json:"website_id" bson:"website_id"`             // ID of the website this status belongs to
+	IsUp            bool               `json:"is_up" bson:"is_up"`                       // Whether the site is up
+	StatusCode      int                `json:"status_code" bson:"status_code"`           // HTTP status code
+	ResponseTime    int64              `json:"response_time_ms" bson:"response_time_ms"` // Response time in milliseconds
+	CheckedAt       int64              `json:"checked_at" bson:"checked_at"`             // Unix timestamp of check
+	CheckedAtDate   time.Time          `json:"checked_at_date" bson:"checked_at_date,omitempty"`
+	Attempts        int                `json:"attempts" bson:"attempts"`                                     // Requests made for this check, including retries
+	Error           string             `json:"error,omitempty" bson:"error,omitempty"`                       // Request error, if the check failed before getting a response
+	Maintenance     bool               `json:"maintenance,omitempty" bson:"maintenance,omitempty"`           // Checked during a maintenance window; excluded from uptime
+	Degraded        bool               `json:"degraded,omitempty" bson:"degraded,omitempty"`                 // Up, but the website was degraded (see ApplyLatency)
+	JobDurationMs   int64              `json:"job_duration_ms,omitempty" bson:"job_duration_ms,omitempty"`   // Runtime the job reported or was measured at (push monitors)
+	Timings         *Timings           `json:"timings,omitempty" bson:"timings,omitempty"`                   // Breakdown of ResponseTime (HTTP checks)
+	FailedAssertion string             `json:"failed_assertion,omitempty" bson:"failed_assertion,omitempty"` // Why a response assertion failed, if one did
+}
+
+// Timings breaks an HTTP check's response time down into its phases.
+// Phases repeated across redirects are summed.
+type Timings struct {
+	DNSMs      int64 `json:"dns_ms" bson:"dns_ms"`           // DNS lookup
+	ConnectMs  int64 `json:"connect_ms" bson:"connect_ms"`   // TCP connect
+	TLSMs      int64 `json:"tls_ms" bson:"tls_ms"`           // TLS handshake
+	TTFBMs     int64 `json:"ttfb_ms" bson:"ttfb_ms"`         // From request sent to first response byte
+	TransferMs int64 `json:"transfer_ms" bson:"transfer_ms"` // From first byte to end of body
+}
+
+// Assertion types
+const (
+	AssertContains    = "contains"     // Body contains Value
+	AssertNotContains = "not_contains" // Body does not contain Value
+	AssertRegex       = "regex"        // Body matches the regular expression in Value
+	AssertJSONPath    = "json_path"    // The JSON value at Path compares to Value using Operator
+	AssertMaxSize     = "max_size"     // Body is at most Value bytes
+)
+
+// Assertion is a check run against a response body
+type Assertion struct {
+	Type     string `json:"type" bson:"type"`                             // One of the Assert* constants
+	Path     string `json:"path,omitempty" bson:"path,omitempty"`         // JSONPath such as $.status or $.checks[0].ok (json_path only)
+	Operator string `json:"operator,omitempty" bson:"operator,omitempty"` // eq, ne, gt, gte, lt, lte or contains (json_path only, default eq)
+	Value    string `json:"value" bson:"value"`                           // Keyword, pattern, expected value or byte limit
 }
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAckLinkVerify(t *testing.T) {
+	t.Setenv("ALERT_LINK_SECRET", "test-secret")
+	t.Setenv("PUBLIC_API_URL", "https://pulsewatch.example.com/")
+	links := NewAckLinkService()
+
+	link, err := url.Parse(links.URL("incident/1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(link.String(), "https://pulsewatch.example.com/api/ack/incident%2F1?") {
+		t.Fatalf("URL() = %s", link)
+	}
+	expires, sig := link.Query().Get("expires"), link.Query().Get("sig")
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	later := strconv.FormatInt(time.Now().Add(30*24*time.Hour).Unix(), 10)
+	tampered := []byte(sig)
+	tampered[0] ^= 1
+
+	tests := []struct {
+		name       string
+		incidentID string
+		expires    string
+		sig        string
+		wantErr    string
+	}{
+		{"valid", "incident/1", expires, sig, ""},
+		{"tampered signature", "incident/1", expires, string(tampered), "invalid link"},
+		{"missing signature", "incident/1", expires, "", "invalid link"},
+		{"another incident", "incident/2", expires, sig, "invalid link"},
+		{"extended expiry", "incident/1", later, sig, "invalid link"},
+		{"invalid expiry", "incident/1", "soon", sig, "invalid link"},
+		{"expired", "incident/1", past, links.sign("incident/1", past), "link expired"},
+	}
+	for _, tt := range tests {
+		err := links.Verify(tt.incidentID, tt.expires, tt.sig)
+		if (err == nil) != (tt.wantErr == "") || (err != nil && err.Error() != tt.wantErr) {
+			t.Errorf("%s: Verify() = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+
+	// Rotating the secret invalidates outstanding links
+	t.Setenv("ALERT_LINK_SECRET", "rotated")
+	if err := NewAckLinkService().Verify("incident/1", expires, sig); err == nil {
+		t.Errorf("Verify() accepted a link signed with the old secret")
+	}
+}
+
+func TestAckLinkDisabled(t *testing.T) {
+	t.Setenv("ALERT_LINK_SECRET", "")
+	t.Setenv("PUBLIC_API_URL", "https://pulsewatch.example.com")
+	links := NewAckLinkService()
+	if got := links.URL("incident"); got != "" {
+		t.Errorf("URL() = %q without a secret, want none", got)
+	}
+	if err := links.Verify("incident", "9999999999", links.sign("incident", "9999999999")); err == nil {
+		t.Errorf("Verify() accepted a link without a secret")
+	}
+
+	t.Setenv("ALERT_LINK_SECRET", "test-secret")
+	t.Setenv("PUBLIC_API_URL", "")
+	t.Setenv("RENDER_EXTERNAL_URL", "")
+	if got := NewAckLinkService().URL("incident"); got != "" {
+		t.Errorf("URL() = %q without a base URL, want none", got)
+	}
+}
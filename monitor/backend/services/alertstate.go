@@ -0,0 +1,145 @@
+package services
+
+import (
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// FailureThreshold returns how many failed checks in a row mark a website down
+func FailureThreshold(website models.Website) int {
+	if website.FailureThreshold <= 0 {
+		return 1
+	}
+	return website.FailureThreshold
+}
+
+// RecoveryThreshold returns how many successful checks in a row mark a website up again
+func RecoveryThreshold(website models.Website) int {
+	if website.RecoveryThreshold <= 0 {
+		return 1
+	}
+	return website.RecoveryThreshold
+}
+
+const (
+	// flapWindow is how many recent check results flap detection looks at
+	flapWindow = 20
+	// flapMinSamples is how many results are needed before a website can flap
+	flapMinSamples = 10
+	// defaultFlapThreshold is the percent of state changes that counts as flapping
+	defaultFlapThreshold = 30
+)
+
+// FlapThreshold returns the percent of state changes across recent checks
+// at which a website is considered flapping. It stops flapping once the
+// rate falls below half of this.
+func FlapThreshold(website models.Website) int {
+	if website.FlapThreshold <= 0 {
+		return defaultFlapThreshold
+	}
+	return website.FlapThreshold
+}
+
+// TransitionRate returns the percent of consecutive results that differ
+func TransitionRate(results []bool) int {
+	if len(results) < 2 {
+		return 0
+	}
+	transitions := 0
+	for i := 1; i < len(results); i++ {
+		if results[i] != results[i-1] {
+			transitions++
+		}
+	}
+	return transitions * 100 / (len(results) - 1)
+}
+
+// updateFlapping adds a result to the state's sliding window and returns
+// AlertFlapping or AlertStabilized when the website starts or stops flapping
+func updateFlapping(state *models.MonitorState, website models.Website, isUp bool, now time.Time) string {
+	state.RecentResults = append(state.RecentResults, isUp)
+	if len(state.RecentResults) > flapWindow {
+		state.RecentResults = state.RecentResults[len(state.RecentResults)-flapWindow:]
+	}
+
+	if website.DisableFlapDetection {
+		if state.Flapping {
+			state.Flapping = false
+			state.FlappingSince = 0
+			return models.AlertStabilized
+		}
+		return ""
+	}
+
+	rate := TransitionRate(state.RecentResults)
+	switch {
+	case !state.Flapping && len(state.RecentResults) >= flapMinSamples && rate >= FlapThreshold(website):
+		state.Flapping = true
+		state.FlappingSince = now.Unix()
+		return models.AlertFlapping
+	case state.Flapping && rate < FlapThreshold(website)/2:
+		state.Flapping = false
+		state.FlappingSince = 0
+		return models.AlertStabilized
+	}
+	return ""
+}
+
+// ApplyCheckResult records a check result on a monitor's state and returns
+// the alert that should be sent for it, or "" if none is due.
+// The state only flips once FailureThreshold failures or RecoveryThreshold
+// successes have been seen in a row. A down alert is sent once per outage
+// and an up alert only follows a down alert, so repeated results and
+// restarts never re-alert. While the website flaps, transitions are damped:
+// a single flapping alert is sent when it starts and a stabilized alert,
+// reporting the settled state, when it stops.
+func ApplyCheckResult(state *models.MonitorState, website models.Website, isUp bool, now time.Time) string {
+	if state.LastCheckedAt == 0 {
+		// Never checked: assume up until proven otherwise
+		state.IsUp = true
+		state.LastChangedAt = now.Unix()
+	}
+	state.LastCheckedAt = now.Unix()
+
+	if isUp {
+		state.ConsecutiveFailures = 0
+		state.ConsecutiveSuccesses++
+	} else {
+		state.ConsecutiveSuccesses = 0
+		state.ConsecutiveFailures++
+	}
+
+	switch {
+	case state.IsUp && state.ConsecutiveFailures >= FailureThreshold(website):
+		state.IsUp = false
+		state.LastChangedAt = now.Unix()
+	case !state.IsUp && state.ConsecutiveSuccesses >= RecoveryThreshold(website):
+		state.IsUp = true
+		state.LastChangedAt = now.Unix()
+	}
+
+	alert := ""
+	switch flap := updateFlapping(state, website, isUp, now); {
+	case flap != "":
+		alert = flap
+	case state.Flapping:
+		// Damped until the website stabilizes
+	case !state.IsUp && state.LastAlert != models.AlertDown:
+		alert = models.AlertDown
+	case state.IsUp && state.LastAlert == models.AlertDown:
+		alert = models.AlertUp
+	}
+	if alert != "" {
+		state.LastAlert = alert
+		if alert == models.AlertStabilized {
+			// Later alerts follow on from the settled state
+			state.LastAlert = models.AlertUp
+			if !state.IsUp {
+				state.LastAlert = models.AlertDown
+			}
+		}
+		state.LastAlertAt = now.Unix()
+	}
+	return alert
+}
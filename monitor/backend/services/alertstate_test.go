@@ -0,0 +1,182 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestApplyCheckResult(t *testing.T) {
+	const (
+		up   = true
+		down = false
+	)
+	tests := []struct {
+		name    string
+		website models.Website
+		results []bool
+		want    []string // Alert returned for each result
+		wantUp  bool
+	}{
+		{
+			name:    "first check up sends nothing",
+			results: []bool{up},
+			want:    []string{""},
+			wantUp:  true,
+		},
+		{
+			name:    "down then up alerts once each",
+			results: []bool{down, down, up, up},
+			want:    []string{models.AlertDown, "", models.AlertUp, ""},
+			wantUp:  true,
+		},
+		{
+			name:    "failure threshold delays down",
+			website: models.Website{FailureThreshold: 3},
+			results: []bool{down, down, up, down, down, down},
+			want:    []string{"", "", "", "", "", models.AlertDown},
+			wantUp:  false,
+		},
+		{
+			name:    "recovery threshold delays up",
+			website: models.Website{RecoveryThreshold: 2},
+			results: []bool{down, up, down, up, up},
+			want:    []string{models.AlertDown, "", "", "", models.AlertUp},
+			wantUp:  true,
+		},
+		{
+			name:    "flapping damps transitions",
+			results: []bool{up, down, up, down, up, down, up, down, up, down, up, down},
+			want: []string{
+				"", models.AlertDown, models.AlertUp, models.AlertDown, models.AlertUp, models.AlertDown,
+				models.AlertUp, models.AlertDown, models.AlertUp, models.AlertFlapping, "", "",
+			},
+			wantUp: false,
+		},
+		{
+			name:    "disabled flap detection alerts every transition",
+			website: models.Website{DisableFlapDetection: true},
+			results: []bool{up, down, up, down, up, down, up, down, up, down, up},
+			want: []string{
+				"", models.AlertDown, models.AlertUp, models.AlertDown, models.AlertUp, models.AlertDown,
+				models.AlertUp, models.AlertDown, models.AlertUp, models.AlertDown, models.AlertUp,
+			},
+			wantUp: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &models.MonitorState{}
+			now := time.Unix(1700000000, 0)
+			var got []string
+			for _, result := range tt.results {
+				got = append(got, ApplyCheckResult(state, tt.website, result, now))
+				now = now.Add(time.Minute)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alerts = %q, want %q", got, tt.want)
+			}
+			if state.IsUp != tt.wantUp {
+				t.Errorf("IsUp = %v, want %v", state.IsUp, tt.wantUp)
+			}
+		})
+	}
+}
+
+func TestApplyCheckResultStabilized(t *testing.T) {
+	state := &models.MonitorState{}
+	now := time.Unix(1700000000, 0)
+	website := models.Website{}
+	for i := 0; i < 10; i++ {
+		ApplyCheckResult(state, website, i%2 == 0, now)
+	}
+	if !state.Flapping {
+		t.Fatal("expected the website to be flapping")
+	}
+
+	// Settling down brings the transition rate under half the threshold
+	var alerts []string
+	for i := 0; i < 20; i++ {
+		if alert := ApplyCheckResult(state, website, false, now); alert != "" {
+			alerts = append(alerts, alert)
+		}
+	}
+	if want := []string{models.AlertStabilized}; !reflect.DeepEqual(alerts, want) {
+		t.Fatalf("alerts = %q, want %q", alerts, want)
+	}
+	if state.Flapping || state.IsUp || state.LastAlert != models.AlertDown {
+		t.Errorf("state = flapping %v, up %v, last alert %q; want settled down", state.Flapping, state.IsUp, state.LastAlert)
+	}
+
+	// Recovering after stabilizing down sends an up alert
+	if alert := ApplyCheckResult(state, website, true, now); alert != models.AlertUp {
+		t.Errorf("alert after recovering = %q, want %q", alert, models.AlertUp)
+	}
+}
+
+func TestTransitionRate(t *testing.T) {
+	tests := []struct {
+		results []bool
+		want    int
+	}{
+		{nil, 0},
+		{[]bool{true}, 0},
+		{[]bool{true, true, true}, 0},
+		{[]bool{true, false, true}, 100},
+		{[]bool{true, false, false, false, true}, 50},
+	}
+	for _, tt := range tests {
+		if got := TransitionRate(tt.results); got != tt.want {
+			t.Errorf("TransitionRate(%v) = %d, want %d", tt.results, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmationThresholds(t *testing.T) {
+	tests := []struct {
+		website      models.Website
+		wantFailure  int
+		wantRecovery int
+	}{
+		{models.Website{}, 1, 1},
+		{models.Website{FailureThreshold: -2, RecoveryThreshold: -1}, 1, 1},
+		{models.Website{FailureThreshold: 3, RecoveryThreshold: 2}, 3, 2},
+	}
+	for _, tt := range tests {
+		if got := FailureThreshold(tt.website); got != tt.wantFailure {
+			t.Errorf("FailureThreshold(%d) = %d, want %d", tt.website.FailureThreshold, got, tt.wantFailure)
+		}
+		if got := RecoveryThreshold(tt.website); got != tt.wantRecovery {
+			t.Errorf("RecoveryThreshold(%d) = %d, want %d", tt.website.RecoveryThreshold, got, tt.wantRecovery)
+		}
+	}
+}
+
+func TestApplyCheckResultCounters(t *testing.T) {
+	website := models.Website{FailureThreshold: 2, RecoveryThreshold: 2}
+	state := &models.MonitorState{}
+	now := time.Unix(1700000000, 0)
+	steps := []struct {
+		isUp          bool
+		wantFailures  int
+		wantSuccesses int
+		wantUp        bool
+	}{
+		{false, 1, 0, true}, // Assumed up until confirmed down
+		{false, 2, 0, false},
+		{true, 0, 1, false},
+		{true, 0, 2, true},
+		{false, 1, 0, true},
+	}
+	for i, step := range steps {
+		ApplyCheckResult(state, website, step.isUp, now)
+		if state.ConsecutiveFailures != step.wantFailures || state.ConsecutiveSuccesses != step.wantSuccesses || state.IsUp != step.wantUp {
+			t.Errorf("check %d: failures %d, successes %d, up %v; want %d, %d, %v", i+1,
+				state.ConsecutiveFailures, state.ConsecutiveSuccesses, state.IsUp, step.wantFailures, step.wantSuccesses, step.wantUp)
+		}
+		now = now.Add(time.Minute)
+	}
+}
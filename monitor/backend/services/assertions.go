@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"github.com/prateeks007/PulseWatch/monitor/backend/utils"
+)
+
+// defaultBodyReadLimit is how much of a response body is read for
+// assertions. Bodies past it are truncated, and a not_contains assertion
+// on a truncated body fails since the rest can't be ruled out.
+const defaultBodyReadLimit = 1 << 20 // 1MB
+
+// bodyReadLimit returns how many bytes must be read to evaluate the
+// assertions. One byte past a max_size limit is read to detect oversize bodies.
+func bodyReadLimit(assertions []models.Assertion) int64 {
+	limit := int64(defaultBodyReadLimit)
+	for _, a := range assertions {
+		if a.Type != models.AssertMaxSize {
+			continue
+		}
+		if max, err := strconv.ParseInt(a.Value, 10, 64); err == nil && max+1 > limit {
+			limit = max + 1
+		}
+	}
+	return limit
+}
+
+// CheckAssertions runs assertions against a response body in order and
+// returns a description of the first one that fails, or "" if all pass.
+// truncated reports that body is only the start of a longer response.
+func CheckAssertions(assertions []models.Assertion, body []byte, truncated bool) string {
+	var doc interface{}
+	var docErr error
+	docParsed := false
+
+	for _, a := range assertions {
+		switch a.Type {
+		case models.AssertContains:
+			if !bytes.Contains(body, []byte(a.Value)) {
+				return fmt.Sprintf("body does not contain %q", a.Value)
+			}
+		case models.AssertNotContains:
+			if bytes.Contains(body, []byte(a.Value)) {
+				return fmt.Sprintf("body contains %q", a.Value)
+			}
+			if truncated {
+				return fmt.Sprintf("body is larger than the %d bytes read, so it may contain %q", len(body), a.Value)
+			}
+		case models.AssertRegex:
+			re, err := regexp.Compile(a.Value)
+			if err != nil {
+				return fmt.Sprintf("invalid regex %q: %v", a.Value, err)
+			}
+			if !re.Match(body) {
+				return fmt.Sprintf("body does not match /%s/", a.Value)
+			}
+		case models.AssertMaxSize:
+			max, err := strconv.ParseInt(a.Value, 10, 64)
+			if err != nil {
+				return fmt.Sprintf("invalid max size %q", a.Value)
+			}
+			if int64(len(body)) > max {
+				return fmt.Sprintf("response size exceeds %d bytes", max)
+			}
+		case models.AssertJSONPath:
+			if !docParsed {
+				docErr = json.Unmarshal(body, &doc)
+				docParsed = true
+			}
+			if docErr != nil {
+				return fmt.Sprintf("body is not valid JSON: %v", docErr)
+			}
+			actual, err := utils.EvalJSONPath(doc, a.Path)
+			if err != nil {
+				return err.Error()
+			}
+			operator := a.Operator
+			if operator == "" {
+				operator = "eq"
+			}
+			if !compareJSONValue(actual, operator, a.Value) {
+				return fmt.Sprintf("%s is %s, expected %s %s", a.Path, formatJSONValue(actual), operator, a.Value)
+			}
+		default:
+			return fmt.Sprintf("unknown assertion type %q", a.Type)
+		}
+	}
+	return ""
+}
+
+// compareJSONValue compares a decoded JSON value with an expected value.
+// Numbers are compared numerically when both sides are numeric; everything
+// else is compared as text.
+func compareJSONValue(actual interface{}, operator, expected string) bool {
+	text := formatJSONValue(actual)
+
+	if number, ok := actual.(float64); ok {
+		if want, err := strconv.ParseFloat(expected, 64); err == nil {
+			switch operator {
+			case "eq":
+				return number == want
+			case "ne":
+				return number != want
+			case "gt":
+				return number > want
+			case "gte":
+				return number >= want
+			case "lt":
+				return number < want
+			case "lte":
+				return number <= want
+			}
+		}
+	}
+
+	switch operator {
+	case "eq":
+		return text == expected
+	case "ne":
+		return text != expected
+	case "contains":
+		return strings.Contains(text, expected)
+	case "gt":
+		return text > expected
+	case "gte":
+		return text >= expected
+	case "lt":
+		return text < expected
+	case "lte":
+		return text <= expected
+	}
+	return false
+}
+
+// formatJSONValue renders a decoded JSON value the way users write it
+func formatJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestCheckAssertions(t *testing.T) {
+	body := []byte(`{"status": "ok", "version": "1.10.2", "db": {"healthy": true, "latency_ms": 12}, "nodes": [1, 2, 3]}`)
+	contains := func(value string) models.Assertion {
+		return models.Assertion{Type: models.AssertContains, Value: value}
+	}
+	jsonPath := func(path, operator, value string) models.Assertion {
+		return models.Assertion{Type: models.AssertJSONPath, Path: path, Operator: operator, Value: value}
+	}
+
+	tests := []struct {
+		name       string
+		assertions []models.Assertion
+		body       []byte
+		truncated  bool
+		want       string // Substring of the failure, "" if all pass
+	}{
+		{name: "no assertions", body: body},
+		{name: "contains", assertions: []models.Assertion{contains(`"status": "ok"`)}, body: body},
+		{name: "missing keyword", assertions: []models.Assertion{contains("maintenance")}, body: body, want: `body does not contain "maintenance"`},
+		{
+			name:       "not contains",
+			assertions: []models.Assertion{{Type: models.AssertNotContains, Value: "error"}},
+			body:       body,
+		},
+		{
+			name:       "forbidden keyword",
+			assertions: []models.Assertion{{Type: models.AssertNotContains, Value: "healthy"}},
+			body:       body,
+			want:       `body contains "healthy"`,
+		},
+		{
+			name:       "not contains on a truncated body",
+			assertions: []models.Assertion{{Type: models.AssertNotContains, Value: "error"}},
+			body:       body,
+			truncated:  true,
+			want:       "may contain",
+		},
+		{name: "regex", assertions: []models.Assertion{{Type: models.AssertRegex, Value: `"version": "1\.\d+`}}, body: body},
+		{name: "regex mismatch", assertions: []models.Assertion{{Type: models.AssertRegex, Value: `^<html`}}, body: body, want: "does not match"},
+		{name: "invalid regex", assertions: []models.Assertion{{Type: models.AssertRegex, Value: `(`}}, body: body, want: "invalid regex"},
+		{name: "max size", assertions: []models.Assertion{{Type: models.AssertMaxSize, Value: "1024"}}, body: body},
+		{name: "too large", assertions: []models.Assertion{{Type: models.AssertMaxSize, Value: "10"}}, body: body, want: "exceeds 10 bytes"},
+		{name: "json equals", assertions: []models.Assertion{jsonPath("$.status", "", "ok")}, body: body},
+		{name: "json bool", assertions: []models.Assertion{jsonPath("$.db.healthy", "eq", "true")}, body: body},
+		{name: "json numeric", assertions: []models.Assertion{jsonPath("$.db.latency_ms", "lt", "100")}, body: body},
+		{name: "json numeric failure", assertions: []models.Assertion{jsonPath("$.db.latency_ms", "gt", "100")}, body: body, want: "$.db.latency_ms is 12, expected gt 100"},
+		{name: "json array index", assertions: []models.Assertion{jsonPath("$.nodes[-1]", "gte", "3")}, body: body},
+		{name: "json contains", assertions: []models.Assertion{jsonPath("$.version", "contains", "1.10")}, body: body},
+		{name: "json missing key", assertions: []models.Assertion{jsonPath("$.uptime", "eq", "1")}, body: body, want: `key "uptime" not found`},
+		{name: "json on a non-JSON body", assertions: []models.Assertion{jsonPath("$.status", "eq", "ok")}, body: []byte("<html>"), want: "not valid JSON"},
+		{
+			name:       "first failure wins",
+			assertions: []models.Assertion{contains("status"), contains("missing"), jsonPath("$.status", "eq", "down")},
+			body:       body,
+			want:       `body does not contain "missing"`,
+		},
+		{name: "unknown type", assertions: []models.Assertion{{Type: "xpath"}}, body: body, want: "unknown assertion type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CheckAssertions(tt.assertions, tt.body, tt.truncated)
+			if (got == "") != (tt.want == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("CheckAssertions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareJSONValue(t *testing.T) {
+	tests := []struct {
+		actual   interface{}
+		operator string
+		expected string
+		want     bool
+	}{
+		{float64(10), "eq", "10", true},
+		{float64(10), "eq", "10.0", true},
+		{float64(9), "gt", "10", false},
+		{float64(10), "gte", "10", true},
+		{float64(9), "lt", "10", true},
+		{float64(10), "ne", "11", true},
+		{"abc", "eq", "abc", true},
+		{"abc", "ne", "abc", false},
+		{"b", "gt", "a", true},
+		{true, "eq", "true", true},
+		{nil, "eq", "null", true},
+		{"abc", "like", "abc", false},
+	}
+	for _, tt := range tests {
+		if got := compareJSONValue(tt.actual, tt.operator, tt.expected); got != tt.want {
+			t.Errorf("compareJSONValue(%v, %s, %s) = %v, want %v", tt.actual, tt.operator, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestBodyReadLimit(t *testing.T) {
+	tests := []struct {
+		assertions []models.Assertion
+		want       int64
+	}{
+		{nil, defaultBodyReadLimit},
+		{[]models.Assertion{{Type: models.AssertMaxSize, Value: "100"}}, defaultBodyReadLimit},
+		{[]models.Assertion{{Type: models.AssertMaxSize, Value: "5000000"}}, 5000001},
+		{[]models.Assertion{{Type: models.AssertMaxSize, Value: "bogus"}}, defaultBodyReadLimit},
+	}
+	for _, tt := range tests {
+		if got := bodyReadLimit(tt.assertions); got != tt.want {
+			t.Errorf("bodyReadLimit(%+v) = %d, want %d", tt.assertions, got, tt.want)
+		}
+	}
+}
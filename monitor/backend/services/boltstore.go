@@ -0,0 +1,959 @@
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	bolt "go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Buckets of the embedded store, named after the Mongo collections. The
+// statuses bucket holds one nested bucket per website, keyed by check time.
+const (
+	boltWebsites    = "websites"
+	boltStatuses    = "statuses"
+	boltSSL         = "ssl"
+	boltSSLHistory  = "ssl_history"
+	boltDomains     = "domains"
+	boltUsers       = "users"
+	boltStates      = "monitor_states"
+	boltIncidents   = "incidents"
+	boltMaintenance = "maintenance_windows"
+)
+
+// maxBoltStatuses matches the number of statuses the Mongo store returns
+const maxBoltStatuses = 500
+
+// BoltStore is the Store kept in a single bbolt file, for self-hosting
+// without an external database. Documents are stored as BSON so they have
+// the same shape as in MongoDB.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the bbolt file at path
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{boltWebsites, boltStatuses, boltSSL, boltSSLHistory, boltDomains, boltUsers, boltStates, boltIncidents, boltMaintenance} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create buckets in %s: %w", path, err)
+	}
+	log.Printf("✅ Embedded storage opened at %s", path)
+	return &BoltStore{db: db}, nil
+}
+
+// Close closes the bbolt file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// --- Encoding ---
+
+// boltGet decodes the document stored under key into out, reporting
+// whether there was one
+func boltGet(tx *bolt.Tx, bucket, key string, out interface{}) (bool, error) {
+	data := tx.Bucket([]byte(bucket)).Get([]byte(key))
+	if data == nil {
+		return false, nil
+	}
+	if err := bson.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("failed to decode %s %s: %w", bucket, key, err)
+	}
+	return true, nil
+}
+
+// boltPut stores doc under key
+func boltPut(tx *bolt.Tx, bucket, key string, doc interface{}) error {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s %s: %w", bucket, key, err)
+	}
+	return tx.Bucket([]byte(bucket)).Put([]byte(key), data)
+}
+
+// boltEach calls fn with every document of a bucket, in key order
+func boltEach(tx *bolt.Tx, bucket string, fn func(data []byte) error) error {
+	return tx.Bucket([]byte(bucket)).ForEach(func(_, data []byte) error {
+		return fn(data)
+	})
+}
+
+// statusKey orders a website's statuses by check time; seq keeps checks
+// made in the same second apart
+func statusKey(checkedAt int64, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(checkedAt))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
+
+// --- Websites ---
+
+func (s *BoltStore) GetWebsites() ([]models.Website, error) {
+	return s.findWebsites(func(models.Website) bool { return true })
+}
+
+// GetWebsitesByUser returns websites filtered by user_id
+func (s *BoltStore) GetWebsitesByUser(userID string) ([]models.Website, error) {
+	return s.findWebsites(func(w models.Website) bool { return w.UserID == userID })
+}
+
+// findWebsites returns the websites match accepts
+func (s *BoltStore) findWebsites(match func(models.Website) bool) ([]models.Website, error) {
+	var sites []models.Website
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltEach(tx, boltWebsites, func(data []byte) error {
+			var website models.Website
+			if err := bson.Unmarshal(data, &website); err != nil {
+				return err
+			}
+			if match(website) {
+				sites = append(sites, website)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find websites: %w", err)
+	}
+	return sites, nil
+}
+
+// GetWebsiteByUser returns a website only if it belongs to the user
+func (s *BoltStore) GetWebsiteByUser(id, userID string) (*models.Website, error) {
+	var website models.Website
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		found, err = boltGet(tx, boltWebsites, id, &website)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find website: %w", err)
+	}
+	if !found || website.UserID != userID {
+		return nil, fmt.Errorf("website not found or access denied")
+	}
+	return &website, nil
+}
+
+// GetWebsiteByPushToken returns the push monitor with the given ping token
+func (s *BoltStore) GetWebsiteByPushToken(token string) (*models.Website, error) {
+	sites, err := s.findWebsites(func(w models.Website) bool {
+		return w.Type == models.MonitorPush && w.PushToken == token
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find push monitor: %w", err)
+	}
+	if len(sites) == 0 {
+		return nil, fmt.Errorf("push monitor not found")
+	}
+	return &sites[0], nil
+}
+
+func (s *BoltStore) SaveWebsite(website models.Website) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, boltWebsites, website.ID, website)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save website %s: %w", website.Name, err)
+	}
+	return nil
+}
+
+func (s *BoltStore) DeleteWebsite(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket([]byte(boltWebsites)).Delete([]byte(id)); err != nil {
+			return err
+		}
+		return deleteWebsiteData(tx, id)
+	})
+}
+
+// DeleteWebsiteByUser deletes a website only if it belongs to the user
+func (s *BoltStore) DeleteWebsiteByUser(id, userID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var website models.Website
+		found, err := boltGet(tx, boltWebsites, id, &website)
+		if err != nil {
+			return err
+		}
+		if !found || website.UserID != userID {
+			return fmt.Errorf("website not found or access denied")
+		}
+		if err := tx.Bucket([]byte(boltWebsites)).Delete([]byte(id)); err != nil {
+			return err
+		}
+		return deleteWebsiteData(tx, id)
+	})
+}
+
+// deleteWebsiteData deletes a website's statuses, SSL info, certificate
+// history, monitor state and incidents
+func deleteWebsiteData(tx *bolt.Tx, id string) error {
+	if err := tx.Bucket([]byte(boltStatuses)).DeleteBucket([]byte(id)); err != nil && err != bolt.ErrBucketNotFound {
+		return err
+	}
+	for _, bucket := range []string{boltSSL, boltStates} {
+		if err := tx.Bucket([]byte(bucket)).Delete([]byte(id)); err != nil {
+			return err
+		}
+	}
+
+	history := tx.Bucket([]byte(boltSSLHistory)).Cursor()
+	prefix := []byte(id + ":")
+	for k, _ := history.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)); k, _ = history.Seek(prefix) {
+		if err := history.Delete(); err != nil {
+			return err
+		}
+	}
+
+	var incidentIDs []string
+	err := boltEach(tx, boltIncidents, func(data []byte) error {
+		var incident models.Incident
+		if err := bson.Unmarshal(data, &incident); err != nil {
+			return err
+		}
+		if incident.WebsiteID == id {
+			incidentIDs = append(incidentIDs, incident.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, incidentID := range incidentIDs {
+		if err := tx.Bucket([]byte(boltIncidents)).Delete([]byte(incidentID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// --- Statuses ---
+
+func (s *BoltStore) SaveStatus(status models.WebsiteStatus) error {
+	if status.ID.IsZero() {
+		status.ID = primitive.NewObjectID()
+	}
+	status.CheckedAtDate = time.Unix(status.CheckedAt, 0)
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		statuses, err := tx.Bucket([]byte(boltStatuses)).CreateBucketIfNotExists([]byte(status.WebsiteID))
+		if err != nil {
+			return err
+		}
+		seq, err := statuses.NextSequence()
+		if err != nil {
+			return err
+		}
+		data, err := bson.Marshal(status)
+		if err != nil {
+			return err
+		}
+		return statuses.Put(statusKey(status.CheckedAt, seq), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save status for website %s: %w", status.WebsiteID, err)
+	}
+	return nil
+}
+
+// GetWebsiteStatuses returns a website's latest statuses, newest first
+func (s *BoltStore) GetWebsiteStatuses(websiteID string) ([]models.WebsiteStatus, error) {
+	var statuses []models.WebsiteStatus
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltStatuses)).Bucket([]byte(websiteID))
+		if bucket == nil {
+			return nil
+		}
+		cursor := bucket.Cursor()
+		for k, data := cursor.Last(); k != nil && len(statuses) < maxBoltStatuses; k, data = cursor.Prev() {
+			var status models.WebsiteStatus
+			if err := bson.Unmarshal(data, &status); err != nil {
+				return err
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find statuses for website %s: %w", websiteID, err)
+	}
+	return statuses, nil
+}
+
+// GetLatestStatus returns the most recent status of a website, or nil if it has none
+func (s *BoltStore) GetLatestStatus(websiteID string) (*models.WebsiteStatus, error) {
+	var status *models.WebsiteStatus
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(boltStatuses)).Bucket([]byte(websiteID))
+		if bucket == nil {
+			return nil
+		}
+		if k, data := bucket.Cursor().Last(); k != nil {
+			status = &models.WebsiteStatus{}
+			return bson.Unmarshal(data, status)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find latest status for website %s: %w", websiteID, err)
+	}
+	return status, nil
+}
+
+// DeleteStatusesBefore deletes the statuses checked before the given Unix
+// timestamp and returns how many were deleted
+func (s *BoltStore) DeleteStatusesBefore(before int64) (int64, error) {
+	var deleted int64
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(boltStatuses)).ForEachBucket(func(websiteID []byte) error {
+			cursor := tx.Bucket([]byte(boltStatuses)).Bucket(websiteID).Cursor()
+			// Keys start with the check time, so the old ones come first
+			for k, _ := cursor.First(); k != nil && int64(binary.BigEndian.Uint64(k[:8])) < before; k, _ = cursor.First() {
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+				deleted++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old statuses: %w", err)
+	}
+	return deleted, nil
+}
+
+// DeleteOrphanedStatuses deletes the statuses of websites that no longer
+// exist and returns how many were deleted
+func (s *BoltStore) DeleteOrphanedStatuses() (int64, error) {
+	var deleted int64
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		statuses := tx.Bucket([]byte(boltStatuses))
+		websites := tx.Bucket([]byte(boltWebsites))
+
+		var orphaned [][]byte
+		err := statuses.ForEachBucket(func(websiteID []byte) error {
+			if websites.Get(websiteID) == nil {
+				orphaned = append(orphaned, append([]byte(nil), websiteID...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, websiteID := range orphaned {
+			deleted += int64(statuses.Bucket(websiteID).Stats().KeyN)
+			if err := statuses.DeleteBucket(websiteID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned statuses: %w", err)
+	}
+	return deleted, nil
+}
+
+// --- SSL ---
+
+func (s *BoltStore) SaveSSL(info models.SSLInfo) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, boltSSL, info.WebsiteID, info)
+	})
+	if err != nil {
+		log.Printf("SaveSSL put error: %v", err)
+	}
+	return err
+}
+
+func (s *BoltStore) GetSSL(websiteID string) *models.SSLInfo {
+	var out models.SSLInfo
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		found, err = boltGet(tx, boltSSL, websiteID, &out)
+		return err
+	})
+	if err != nil {
+		log.Printf("GetSSL error: %v", err)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+	return &out
+}
+
+// RecordCertificate adds a certificate to its website's history, or
+// refreshes its last_seen if it is already there. It reports whether the
+// certificate is new.
+func (s *BoltStore) RecordCertificate(record models.CertificateRecord) (bool, error) {
+	var isNew bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var existing models.CertificateRecord
+		found, err := boltGet(tx, boltSSLHistory, record.ID, &existing)
+		if err != nil {
+			return err
+		}
+		isNew = !found
+		if found {
+			// Everything but last_seen is kept from when it was first seen
+			existing.LastSeen = record.LastSeen
+			record = existing
+		}
+		return boltPut(tx, boltSSLHistory, record.ID, record)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record certificate: %w", err)
+	}
+	return isNew, nil
+}
+
+// GetCertificateHistory returns the certificates a website has served, newest first
+func (s *BoltStore) GetCertificateHistory(websiteID string) ([]models.CertificateRecord, error) {
+	records := []models.CertificateRecord{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket([]byte(boltSSLHistory)).Cursor()
+		prefix := []byte(websiteID + ":")
+		for k, data := cursor.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)); k, data = cursor.Next() {
+			var record models.CertificateRecord
+			if err := bson.Unmarshal(data, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find certificate history for website %s: %w", websiteID, err)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].FirstSeen > records[j].FirstSeen })
+	return records, nil
+}
+
+// SaveDomain stores the registration of an apex domain
+func (s *BoltStore) SaveDomain(info models.DomainInfo) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, boltDomains, info.Domain, info)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save domain %s: %w", info.Domain, err)
+	}
+	return nil
+}
+
+// GetDomain returns the stored registration of an apex domain, or nil if
+// it hasn't been looked up
+func (s *BoltStore) GetDomain(domain string) (*models.DomainInfo, error) {
+	var info models.DomainInfo
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		found, err = boltGet(tx, boltDomains, domain, &info)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain %s: %w", domain, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &info, nil
+}
+
+// --- User Management ---
+
+// GetUser returns user settings by user ID
+func (s *BoltStore) GetUser(userID string) (*models.User, error) {
+	var user models.User
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		found, err = boltGet(tx, boltUsers, userID, &user)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if !found {
+		return nil, nil // User not found, return nil
+	}
+	return &user, nil
+}
+
+// SaveUser saves or updates user settings
+func (s *BoltStore) SaveUser(user models.User) error {
+	user.UpdatedAt = time.Now().Unix()
+	if user.CreatedAt == 0 {
+		user.CreatedAt = user.UpdatedAt
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return boltPut(tx, boltUsers, user.ID, user)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save user %s: %w", user.ID, err)
+	}
+	return nil
+}
+
+// --- Monitor State ---
+
+// GetMonitorState returns the alerting state of a website, or nil if it has never been checked
+func (s *BoltStore) GetMonitorState(websiteID string) (*models.MonitorState, error) {
+	var state models.MonitorState
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		found, err = boltGet(tx, boltStates, websiteID, &state)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find monitor state for website %s: %w", websiteID, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// UpdateMonitorState applies update to the stored state of a website and
+// saves it. As with MongoDB, update runs outside the write and is retried
+// if another update saved first, so it must only depend on its argument.
+func (s *BoltStore) UpdateMonitorState(websiteID string, update func(*models.MonitorState)) (*models.MonitorState, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		current, err := s.GetMonitorState(websiteID)
+		if err != nil {
+			return nil, err
+		}
+		var state models.MonitorState
+		if current != nil {
+			state = *current
+		}
+
+		version := state.Version
+		update(&state)
+		state.WebsiteID = websiteID
+		state.Version = version + 1
+
+		saved := false
+		err = s.db.Update(func(tx *bolt.Tx) error {
+			var latest models.MonitorState
+			found, err := boltGet(tx, boltStates, websiteID, &latest)
+			if err != nil {
+				return err
+			}
+			if found != (current != nil) || latest.Version != version {
+				return nil // Another check saved first
+			}
+			saved = true
+			return boltPut(tx, boltStates, websiteID, state)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to save monitor state for website %s: %w", websiteID, err)
+		}
+		if saved {
+			return &state, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to save monitor state for website %s: too many concurrent updates", websiteID)
+}
+
+// --- Incidents ---
+
+// SaveIncident inserts a new incident
+func (s *BoltStore) SaveIncident(incident models.Incident) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(boltIncidents)).Get([]byte(incident.ID)) != nil {
+			return fmt.Errorf("incident %s already exists", incident.ID)
+		}
+		return boltPut(tx, boltIncidents, incident.ID, incident)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save incident for website %s: %w", incident.WebsiteID, err)
+	}
+	return nil
+}
+
+// GetOpenIncident returns the open incident of a website, or nil if it has none
+func (s *BoltStore) GetOpenIncident(websiteID string) (*models.Incident, error) {
+	incidents, err := s.findIncidents(func(i models.Incident) bool {
+		return i.WebsiteID == websiteID && i.Status == models.IncidentOpen
+	}, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open incident for website %s: %w", websiteID, err)
+	}
+	if len(incidents) == 0 {
+		return nil, nil
+	}
+	return &incidents[0], nil
+}
+
+// RecordIncidentFailure adds a failed check to a website's open incident.
+// A reason not seen before in the incident is also added to its timeline.
+func (s *BoltStore) RecordIncidentFailure(websiteID string, statusCode int, reason string, at int64) error {
+	_, err := s.updateIncidents(isOpenIncidentOf(websiteID), func(incident *models.Incident) bool {
+		incident.FailedChecks++
+		if statusCode != 0 && !containsInt(incident.StatusCodes, statusCode) {
+			incident.StatusCodes = append(incident.StatusCodes, statusCode)
+		}
+		if reason == "" {
+			return true
+		}
+		incident.LastError = reason
+		// Only a new reason is recorded, and only while under the cap
+		if !containsString(incident.Errors, reason) && len(incident.Errors) < models.MaxIncidentErrors {
+			incident.Errors = append(incident.Errors, reason)
+			incident.Timeline = append(incident.Timeline, models.IncidentEvent{Type: models.EventFailure, At: at, Message: reason})
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update incident for website %s: %w", websiteID, err)
+	}
+	return nil
+}
+
+// AddIncidentEvent appends an event to the timeline of a website's open incident
+func (s *BoltStore) AddIncidentEvent(websiteID string, event models.IncidentEvent) error {
+	_, err := s.updateIncidents(isOpenIncidentOf(websiteID), func(incident *models.Incident) bool {
+		incident.Timeline = append(incident.Timeline, event)
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update incident for website %s: %w", websiteID, err)
+	}
+	return nil
+}
+
+// ResolveIncident resolves the open incident of a website and returns it,
+// or nil if the website had none
+func (s *BoltStore) ResolveIncident(websiteID string, at int64, message string) (*models.Incident, error) {
+	var resolved *models.Incident
+	_, err := s.updateIncidents(isOpenIncidentOf(websiteID), func(incident *models.Incident) bool {
+		incident.Status = models.IncidentResolved
+		incident.ResolvedAt = at
+		incident.DurationSeconds = at - incident.StartedAt
+		incident.Timeline = append(incident.Timeline, models.IncidentEvent{Type: models.EventResolved, At: at, Message: message})
+		if resolved == nil || incident.StartedAt > resolved.StartedAt {
+			copied := *incident
+			resolved = &copied
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve incident of website %s: %w", websiteID, err)
+	}
+	return resolved, nil
+}
+
+// GetIncidentByUser returns an incident only if it belongs to the user
+func (s *BoltStore) GetIncidentByUser(id, userID string) (*models.Incident, error) {
+	incident, err := s.getIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident == nil || incident.UserID != userID {
+		return nil, fmt.Errorf("incident not found or access denied")
+	}
+	return incident, nil
+}
+
+// GetIncidentsByUser returns a user's incidents, newest first, optionally
+// only those with the given status
+func (s *BoltStore) GetIncidentsByUser(userID, status string, limit int64) ([]models.Incident, error) {
+	return s.findIncidents(func(i models.Incident) bool {
+		return i.UserID == userID && (status == "" || i.Status == status)
+	}, limit)
+}
+
+// GetIncidentsByWebsite returns a website's incidents, newest first
+func (s *BoltStore) GetIncidentsByWebsite(websiteID string, limit int64) ([]models.Incident, error) {
+	return s.findIncidents(func(i models.Incident) bool { return i.WebsiteID == websiteID }, limit)
+}
+
+// GetIncidentsSince returns a user's incidents that started at or after
+// since, optionally only those of one website
+func (s *BoltStore) GetIncidentsSince(userID, websiteID string, since int64) ([]models.Incident, error) {
+	return s.findIncidents(func(i models.Incident) bool {
+		return i.UserID == userID && i.StartedAt >= since && (websiteID == "" || i.WebsiteID == websiteID)
+	}, 0)
+}
+
+// AcknowledgeIncident marks an open incident as acknowledged by by. It
+// returns the incident, and whether this call acknowledged it (false if
+// it already was).
+func (s *BoltStore) AcknowledgeIncident(id, by string, at int64) (*models.Incident, bool, error) {
+	incident, acknowledged, err := s.updateIncident(id, func(incident *models.Incident) bool {
+		if incident.Status != models.IncidentOpen || incident.Acknowledged() {
+			return false
+		}
+		incident.AcknowledgedAt = at
+		incident.AcknowledgedBy = by
+		incident.Timeline = append(incident.Timeline, models.IncidentEvent{Type: models.EventAcknowledged, At: at, Message: "Acknowledged by " + by})
+		return true
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acknowledge incident %s: %w", id, err)
+	}
+	if incident == nil {
+		return nil, false, fmt.Errorf("incident not found")
+	}
+	return incident, acknowledged, nil
+}
+
+// AddIncidentNote appends a note to an incident
+func (s *BoltStore) AddIncidentNote(id string, note models.IncidentNote) error {
+	_, _, err := s.updateIncident(id, func(incident *models.Incident) bool {
+		incident.Notes = append(incident.Notes, note)
+		incident.Timeline = append(incident.Timeline, models.IncidentEvent{Type: models.EventNote, At: note.At, Message: note.Text})
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add note to incident %s: %w", id, err)
+	}
+	return nil
+}
+
+// SetIncidentRootCause sets the root cause of an incident
+func (s *BoltStore) SetIncidentRootCause(id, rootCause string, at int64) error {
+	_, _, err := s.updateIncident(id, func(incident *models.Incident) bool {
+		incident.RootCause = rootCause
+		incident.Timeline = append(incident.Timeline, models.IncidentEvent{Type: models.EventRootCause, At: at, Message: rootCause})
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set root cause of incident %s: %w", id, err)
+	}
+	return nil
+}
+
+// GetEscalatingIncidents returns the open, unacknowledged incidents that
+// follow an escalation policy
+func (s *BoltStore) GetEscalatingIncidents() ([]models.Incident, error) {
+	return s.findIncidents(func(i models.Incident) bool {
+		return i.Status == models.IncidentOpen && !i.Acknowledged() && i.EscalationPolicyID != ""
+	}, 0)
+}
+
+// ClaimEscalation records that an incident's escalation advanced to step
+// at the given time. It only succeeds if the incident is still open,
+// unacknowledged and unchanged since it was read.
+func (s *BoltStore) ClaimEscalation(incident models.Incident, step int, at int64, event *models.IncidentEvent) (bool, error) {
+	_, claimed, err := s.updateIncident(incident.ID, func(current *models.Incident) bool {
+		if current.Status != models.IncidentOpen || current.Acknowledged() ||
+			current.EscalationStep != incident.EscalationStep || current.LastNotifiedAt != incident.LastNotifiedAt {
+			return false
+		}
+		current.EscalationStep = step
+		current.LastNotifiedAt = at
+		if event != nil {
+			current.Timeline = append(current.Timeline, *event)
+		}
+		return true
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to update escalation of incident %s: %w", incident.ID, err)
+	}
+	return claimed, nil
+}
+
+// getIncident returns an incident by ID, or nil if there is none
+func (s *BoltStore) getIncident(id string) (*models.Incident, error) {
+	var incident models.Incident
+	var found bool
+	err := s.db.View(func(tx *bolt.Tx) (err error) {
+		found, err = boltGet(tx, boltIncidents, id, &incident)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incident: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &incident, nil
+}
+
+// findIncidents returns the incidents match accepts, newest first
+func (s *BoltStore) findIncidents(match func(models.Incident) bool, limit int64) ([]models.Incident, error) {
+	incidents := []models.Incident{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltEach(tx, boltIncidents, func(data []byte) error {
+			var incident models.Incident
+			if err := bson.Unmarshal(data, &incident); err != nil {
+				return err
+			}
+			if match(incident) {
+				incidents = append(incidents, incident)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incidents: %w", err)
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool { return incidents[i].StartedAt > incidents[j].StartedAt })
+	if limit > 0 && int64(len(incidents)) > limit {
+		incidents = incidents[:limit]
+	}
+	return incidents, nil
+}
+
+// updateIncident applies update to an incident and saves it if update
+// reports a change. It returns the incident after the update, or nil if
+// it doesn't exist, and whether it changed.
+func (s *BoltStore) updateIncident(id string, update func(*models.Incident) bool) (*models.Incident, bool, error) {
+	var incident *models.Incident
+	var changed bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var current models.Incident
+		found, err := boltGet(tx, boltIncidents, id, &current)
+		if err != nil || !found {
+			return err
+		}
+		incident = &current
+		if changed = update(incident); !changed {
+			return nil
+		}
+		return boltPut(tx, boltIncidents, id, incident)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return incident, changed, nil
+}
+
+// updateIncidents applies update to every incident match accepts, saving
+// those update reports changed, and returns how many changed
+func (s *BoltStore) updateIncidents(match func(models.Incident) bool, update func(*models.Incident) bool) (int, error) {
+	changed := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var matched []models.Incident
+		err := boltEach(tx, boltIncidents, func(data []byte) error {
+			var incident models.Incident
+			if err := bson.Unmarshal(data, &incident); err != nil {
+				return err
+			}
+			if match(incident) {
+				matched = append(matched, incident)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range matched {
+			if !update(&matched[i]) {
+				continue
+			}
+			if err := boltPut(tx, boltIncidents, matched[i].ID, matched[i]); err != nil {
+				return err
+			}
+			changed++
+		}
+		return nil
+	})
+	return changed, err
+}
+
+// isOpenIncidentOf matches the open incidents of a website
+func isOpenIncidentOf(websiteID string) func(models.Incident) bool {
+	return func(i models.Incident) bool {
+		return i.WebsiteID == websiteID && i.Status == models.IncidentOpen
+	}
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// --- Maintenance Windows ---
+
+// SaveMaintenanceWindow inserts or replaces a maintenance window
+func (s *BoltStore) SaveMaintenanceWindow(window models.MaintenanceWindow) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var existing models.MaintenanceWindow
+		found, err := boltGet(tx, boltMaintenance, window.ID, &existing)
+		if err != nil {
+			return err
+		}
+		if found && existing.UserID != window.UserID {
+			return fmt.Errorf("window belongs to another user")
+		}
+		return boltPut(tx, boltMaintenance, window.ID, window)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save maintenance window %s: %w", window.Name, err)
+	}
+	return nil
+}
+
+// GetMaintenanceWindowsByUser returns a user's maintenance windows
+func (s *BoltStore) GetMaintenanceWindowsByUser(userID string) ([]models.MaintenanceWindow, error) {
+	windows := []models.MaintenanceWindow{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return boltEach(tx, boltMaintenance, func(data []byte) error {
+			var window models.MaintenanceWindow
+			if err := bson.Unmarshal(data, &window); err != nil {
+				return err
+			}
+			if window.UserID == userID {
+				windows = append(windows, window)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find maintenance windows for user %s: %w", userID, err)
+	}
+	sort.SliceStable(windows, func(i, j int) bool { return windows[i].CreatedAt < windows[j].CreatedAt })
+	return windows, nil
+}
+
+// DeleteMaintenanceWindowByUser deletes a maintenance window only if it belongs to the user
+func (s *BoltStore) DeleteMaintenanceWindowByUser(id, userID string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		var window models.MaintenanceWindow
+		found, err := boltGet(tx, boltMaintenance, id, &window)
+		if err != nil {
+			return fmt.Errorf("failed to delete maintenance window: %w", err)
+		}
+		if !found || window.UserID != userID {
+			return fmt.Errorf("maintenance window not found or access denied")
+		}
+		return tx.Bucket([]byte(boltMaintenance)).Delete([]byte(id))
+	})
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// newTestBoltStore opens a BoltStore in a file removed after the test
+func newTestBoltStore(t *testing.T, path string) *BoltStore {
+	t.Helper()
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "pulsewatch.db")
+	}
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore() error = %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestBoltStore(t *testing.T) {
+	testStore(t, func(t *testing.T) Store {
+		return newTestBoltStore(t, "")
+	})
+}
+
+func TestBoltStoreReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pulsewatch.db")
+	store, err := NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltStore() error = %v", err)
+	}
+	website := models.Website{ID: "w1", UserID: "u1", Name: "Example", URL: "https://example.com", Interval: 60}
+	if err := store.SaveWebsite(website); err != nil {
+		t.Fatalf("SaveWebsite() error = %v", err)
+	}
+	if err := store.SaveStatus(models.WebsiteStatus{WebsiteID: "w1", CheckedAt: 100, IsUp: true}); err != nil {
+		t.Fatalf("SaveStatus() error = %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened := newTestBoltStore(t, path)
+	if got, err := reopened.GetWebsiteByUser("w1", "u1"); err != nil || got.Name != "Example" || got.Interval != 60 {
+		t.Errorf("GetWebsiteByUser() after reopening = %+v, %v, want %+v", got, err, website)
+	}
+	// New statuses sort after the ones saved before reopening
+	if err := reopened.SaveStatus(models.WebsiteStatus{WebsiteID: "w1", CheckedAt: 100, IsUp: false}); err != nil {
+		t.Fatalf("SaveStatus() error = %v", err)
+	}
+	if latest, err := reopened.GetLatestStatus("w1"); err != nil || latest == nil || latest.IsUp {
+		t.Errorf("GetLatestStatus() after reopening = %+v, %v, want the status saved last", latest, err)
+	}
+}
+
+func TestBoltStoreStatusLimit(t *testing.T) {
+	store := newTestBoltStore(t, "")
+	for i := 0; i < maxBoltStatuses+10; i++ {
+		if err := store.SaveStatus(models.WebsiteStatus{WebsiteID: "w1", CheckedAt: int64(1000 + i)}); err != nil {
+			t.Fatalf("SaveStatus() error = %v", err)
+		}
+	}
+	statuses, err := store.GetWebsiteStatuses("w1")
+	if err != nil {
+		t.Fatalf("GetWebsiteStatuses() error = %v", err)
+	}
+	if len(statuses) != maxBoltStatuses || statuses[0].CheckedAt != int64(1000+maxBoltStatuses+9) {
+		t.Errorf("GetWebsiteStatuses() = %d statuses from %d, want the newest %d", len(statuses), statuses[0].CheckedAt, maxBoltStatuses)
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// DescribeCertificate returns the details of a certificate shown in SSL info
+func DescribeCertificate(cert *x509.Certificate) models.CertificateInfo {
+	keyType, keySize := certificateKey(cert)
+	return models.CertificateInfo{
+		Subject:            certificateName(cert.Subject.CommonName, cert.Subject.String()),
+		Issuer:             certificateName(cert.Issuer.CommonName, cert.Issuer.String()),
+		SANs:               certificateSANs(cert),
+		ValidFrom:          cert.NotBefore.Unix(),
+		ValidTo:            cert.NotAfter.Unix(),
+		IsCA:               cert.IsCA,
+		KeyType:            keyType,
+		KeySize:            keySize,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		SerialNumber:       hex.EncodeToString(cert.SerialNumber.Bytes()),
+		Fingerprint:        CertificateFingerprint(cert),
+	}
+}
+
+// CertificateFingerprint returns the hex SHA-256 of a certificate
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// sslRenewalWindow is how long before expiry a replacement certificate
+// counts as a planned renewal
+const sslRenewalWindow = 30 * 24 * time.Hour
+
+// CertificateChanges compares the certificate a website served last with
+// the one it serves now. It returns what changed between them and whether
+// the replacement was unexpected: outside the renewal window, or by a
+// different issuer or key type.
+func CertificateChanges(previous models.CertificateRecord, current models.SSLInfo) (changes []string, unexpected bool) {
+	if previous.Fingerprint == "" || previous.Fingerprint == current.Fingerprint {
+		return nil, false
+	}
+	if previous.Issuer != current.Issuer {
+		changes = append(changes, fmt.Sprintf("Issuer changed from %s to %s", previous.Issuer, current.Issuer))
+		unexpected = true
+	}
+	if previous.KeyType != current.KeyType || previous.KeySize != current.KeySize {
+		changes = append(changes, fmt.Sprintf("Key changed from %s %d to %s %d", previous.KeyType, previous.KeySize, current.KeyType, current.KeySize))
+		unexpected = true
+	}
+	if previous.Subject != current.Subject {
+		changes = append(changes, fmt.Sprintf("Subject changed from %s to %s", previous.Subject, current.Subject))
+	}
+	if left := time.Unix(previous.ValidTo, 0).Sub(time.Unix(current.CheckedAt, 0)); left > sslRenewalWindow {
+		changes = append(changes, fmt.Sprintf("Replaced %d days before it expired", int(left.Hours()/24)))
+		unexpected = true
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "Renewed")
+	}
+	return changes, unexpected
+}
+
+// LatestCertificate returns the certificate in a history that was served
+// most recently, or nil if the history is empty
+func LatestCertificate(history []models.CertificateRecord) *models.CertificateRecord {
+	var latest *models.CertificateRecord
+	for i := range history {
+		if latest == nil || history[i].LastSeen > latest.LastSeen {
+			latest = &history[i]
+		}
+	}
+	return latest
+}
+
+// NewCertificateRecord returns the history record of a checked
+// certificate, describing how it replaced previous if that is known
+func NewCertificateRecord(info models.SSLInfo, previous *models.CertificateRecord) models.CertificateRecord {
+	record := models.CertificateRecord{
+		ID:                 info.WebsiteID + ":" + info.Fingerprint,
+		WebsiteID:          info.WebsiteID,
+		Host:               info.Host,
+		Fingerprint:        info.Fingerprint,
+		Subject:            info.Subject,
+		Issuer:             info.Issuer,
+		SANs:               info.SANs,
+		KeyType:            info.KeyType,
+		KeySize:            info.KeySize,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+		ValidFrom:          info.ValidFrom,
+		ValidTo:            info.ValidTo,
+		FirstSeen:          info.CheckedAt,
+		LastSeen:           info.CheckedAt,
+	}
+	if len(info.Chain) > 0 {
+		record.SerialNumber = info.Chain[0].SerialNumber
+	}
+	if previous != nil && previous.Fingerprint != "" && previous.Fingerprint != info.Fingerprint {
+		record.PreviousFingerprint = previous.Fingerprint
+		record.Changes, record.Unexpected = CertificateChanges(*previous, info)
+	}
+	return record
+}
+
+// VerifyChain verifies a served chain (leaf first) for serverName against
+// roots, or the system roots if nil. It returns "" if the chain is
+// trusted, or one of the models.SSL* reasons and a message saying why not.
+func VerifyChain(certs []*x509.Certificate, serverName string, roots *x509.CertPool, now time.Time) (reason, message string) {
+	if len(certs) == 0 {
+		return models.SSLInvalid, "no peer certificates"
+	}
+	leaf := certs[0]
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	if err == nil {
+		return "", ""
+	}
+
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var authorityErr x509.UnknownAuthorityError
+	switch {
+	case errors.As(err, &hostnameErr):
+		return models.SSLHostnameMismatch, hostnameErr.Error()
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired && invalidErr.Cert == leaf:
+		if now.Before(leaf.NotBefore) {
+			return models.SSLNotYetValid, fmt.Sprintf("certificate is not valid until %s", formatCertificateTime(leaf.NotBefore))
+		}
+		return models.SSLExpired, fmt.Sprintf("certificate expired on %s", formatCertificateTime(leaf.NotAfter))
+	case errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired && invalidErr.Cert != nil:
+		return models.SSLExpiredIntermediate, fmt.Sprintf("intermediate certificate %q expired on %s",
+			certificateName(invalidErr.Cert.Subject.CommonName, invalidErr.Cert.Subject.String()), formatCertificateTime(invalidErr.Cert.NotAfter))
+	case errors.As(err, &authorityErr):
+		// Chain building reports an expired intermediate as an unknown authority
+		for _, cert := range certs[1:] {
+			if now.After(cert.NotAfter) {
+				return models.SSLExpiredIntermediate, fmt.Sprintf("intermediate certificate %q expired on %s",
+					certificateName(cert.Subject.CommonName, cert.Subject.String()), formatCertificateTime(cert.NotAfter))
+			}
+		}
+		if isSelfSigned(leaf) {
+			return models.SSLSelfSigned, "certificate is self-signed"
+		}
+		return models.SSLUnknownAuthority, fmt.Sprintf("certificate signed by unknown authority %q",
+			certificateName(leaf.Issuer.CommonName, leaf.Issuer.String()))
+	default:
+		return models.SSLInvalid, err.Error()
+	}
+}
+
+// isSelfSigned reports whether a certificate is signed by its own key.
+// CheckSignatureFrom isn't used since it rejects self-signed leaves that
+// aren't marked as a CA.
+func isSelfSigned(cert *x509.Certificate) bool {
+	return cert.Subject.String() == cert.Issuer.String() &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+}
+
+// certificateKey returns the type and size in bits of a certificate's public key
+func certificateKey(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return cert.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// certificateSANs returns the DNS names and IP addresses a certificate covers
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// certificateName prefers a common name over the full distinguished name
+func certificateName(commonName, full string) string {
+	if commonName != "" {
+		return commonName
+	}
+	return full
+}
+
+// formatCertificateTime formats a certificate validity bound for messages
+func formatCertificateTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// testCert is a generated certificate and its key
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// testCertOptions describes a certificate to generate
+type testCertOptions struct {
+	name       string
+	isCA       bool
+	notBefore  time.Time
+	notAfter   time.Time
+	hosts      []string // DNS names and IP addresses, for leaves
+	ocspServer string
+}
+
+// newTestCert generates a certificate signed by parent, or self-signed if
+// parent is nil
+func newTestCert(t *testing.T, opts testCertOptions, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.notBefore.IsZero() {
+		opts.notBefore = time.Now().Add(-time.Hour)
+	}
+	if opts.notAfter.IsZero() {
+		opts.notAfter = time.Now().Add(90 * 24 * time.Hour)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: opts.name},
+		NotBefore:             opts.notBefore,
+		NotAfter:              opts.notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  opts.isCA,
+	}
+	if opts.isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+		template.ExtKeyUsage = nil
+	}
+	for _, host := range opts.hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	if opts.ocspServer != "" {
+		template.OCSPServer = []string{opts.ocspServer}
+	}
+
+	issuer, signer := template, crypto.Signer(key)
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// testChain is a root, an intermediate and a leaf for localhost
+type testChain struct {
+	root, intermediate, leaf *testCert
+}
+
+func newTestChain(t *testing.T, intermediateOpts, leafOpts testCertOptions) testChain {
+	t.Helper()
+	root := newTestCert(t, testCertOptions{name: "PulseWatch Test Root", isCA: true}, nil)
+	intermediateOpts.name, intermediateOpts.isCA = "PulseWatch Test Intermediate", true
+	intermediate := newTestCert(t, intermediateOpts, root)
+	leafOpts.name = "localhost"
+	if leafOpts.hosts == nil {
+		leafOpts.hosts = []string{"localhost", "127.0.0.1"}
+	}
+	leaf := newTestCert(t, leafOpts, intermediate)
+	return testChain{root: root, intermediate: intermediate, leaf: leaf}
+}
+
+func (c testChain) roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.root.cert)
+	return pool
+}
+
+// newTLSServer starts an HTTPS stand-in serving certs (leaf first) with key
+func newTLSServer(t *testing.T, key crypto.Signer, certs ...*x509.Certificate) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	chain := tls.Certificate{PrivateKey: key}
+	for _, cert := range certs {
+		chain.Certificate = append(chain.Certificate, cert.Raw)
+	}
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{chain}}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Checks hang up after the handshake
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVerifyChain(t *testing.T) {
+	expired := testCertOptions{notBefore: time.Now().Add(-48 * time.Hour), notAfter: time.Now().Add(-24 * time.Hour)}
+	tests := []struct {
+		name             string
+		intermediateOpts testCertOptions
+		leafOpts         testCertOptions
+		serverName       string
+		selfSigned       bool // Serve a self-signed leaf instead of the chain
+		want             string
+		wantMessage      string
+	}{
+		{
+			name:       "trusted",
+			serverName: "localhost",
+			want:       "",
+		},
+		{
+			name:        "expired leaf",
+			leafOpts:    expired,
+			serverName:  "localhost",
+			want:        models.SSLExpired,
+			wantMessage: "certificate expired on",
+		},
+		{
+			name:        "not yet valid",
+			leafOpts:    testCertOptions{notBefore: time.Now().Add(24 * time.Hour)},
+			serverName:  "localhost",
+			want:        models.SSLNotYetValid,
+			wantMessage: "certificate is not valid until",
+		},
+		{
+			name:             "expired intermediate",
+			intermediateOpts: expired,
+			serverName:       "localhost",
+			want:             models.SSLExpiredIntermediate,
+			wantMessage:      `"PulseWatch Test Intermediate" expired`,
+		},
+		{
+			name:        "hostname mismatch",
+			serverName:  "status.example.com",
+			want:        models.SSLHostnameMismatch,
+			wantMessage: "status.example.com",
+		},
+		{
+			name:        "self-signed",
+			serverName:  "localhost",
+			selfSigned:  true,
+			want:        models.SSLSelfSigned,
+			wantMessage: "certificate is self-signed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newTestChain(t, tt.intermediateOpts, tt.leafOpts)
+			server := newTLSServer(t, chain.leaf.key, chain.leaf.cert, chain.intermediate.cert)
+			if tt.selfSigned {
+				leaf := newTestCert(t, testCertOptions{name: "localhost", hosts: []string{"localhost", "127.0.0.1"}}, nil)
+				server = newTLSServer(t, leaf.key, leaf.cert)
+			}
+
+			addr := server.Listener.Addr().String()
+			conn, err := dialTLS(addr, tt.serverName, models.TLSDirect, 5*time.Second)
+			if err != nil {
+				t.Fatalf("dialTLS() error = %v", err)
+			}
+			defer conn.Close()
+
+			info := DescribeConnection(addr, tt.serverName, conn.ConnectionState(), chain.roots(), time.Now())
+			if info.VerificationReason != tt.want {
+				t.Errorf("VerificationReason = %q, want %q (%s)", info.VerificationReason, tt.want, info.VerificationError)
+			}
+			if info.Verified != (tt.want == "") {
+				t.Errorf("Verified = %v, want %v", info.Verified, tt.want == "")
+			}
+			if !strings.Contains(info.VerificationError, tt.wantMessage) {
+				t.Errorf("VerificationError = %q, want it to contain %q", info.VerificationError, tt.wantMessage)
+			}
+			if info.Error != "" {
+				t.Errorf("Error = %q, want it reserved for failed handshakes", info.Error)
+			}
+			if tt.selfSigned {
+				return
+			}
+			if len(info.Chain) != 2 || info.Chain[1].Subject != "PulseWatch Test Intermediate" || !info.Chain[1].IsCA {
+				t.Errorf("Chain = %+v, want the leaf and intermediate", info.Chain)
+			}
+			if info.Fingerprint != CertificateFingerprint(chain.leaf.cert) || info.KeyType != "ECDSA" || info.KeySize != 256 {
+				t.Errorf("leaf details = %s %s %d, want the served leaf", info.Fingerprint, info.KeyType, info.KeySize)
+			}
+		})
+	}
+}
+
+func TestVerifyChainUnknownAuthority(t *testing.T) {
+	chain := newTestChain(t, testCertOptions{}, testCertOptions{})
+	other := newTestChain(t, testCertOptions{}, testCertOptions{})
+	certs := []*x509.Certificate{chain.leaf.cert, chain.intermediate.cert}
+
+	reason, message := VerifyChain(certs, "localhost", other.roots(), time.Now())
+	if reason != models.SSLUnknownAuthority || !strings.Contains(message, "PulseWatch Test Intermediate") {
+		t.Errorf("VerifyChain() = %q, %q; want an unknown authority", reason, message)
+	}
+	if reason, _ := VerifyChain(nil, "localhost", chain.roots(), time.Now()); reason != models.SSLInvalid {
+		t.Errorf("VerifyChain(nil) = %q, want %q", reason, models.SSLInvalid)
+	}
+}
+
+func TestCertificateChanges(t *testing.T) {
+	const day = 24 * 60 * 60
+	const checkedAt = 1700000000
+	previous := models.CertificateRecord{
+		Fingerprint: "aa",
+		Subject:     "example.com",
+		Issuer:      "R3",
+		KeyType:     "RSA",
+		KeySize:     2048,
+		ValidTo:     checkedAt + 20*day,
+	}
+	renewed := models.SSLInfo{Fingerprint: "bb", Subject: "example.com", Issuer: "R3", KeyType: "RSA", KeySize: 2048, CheckedAt: checkedAt}
+
+	tests := []struct {
+		name           string
+		previous       models.CertificateRecord
+		current        func(info models.SSLInfo) models.SSLInfo
+		want           []string // Prefixes of the changes
+		wantUnexpected bool
+	}{
+		{
+			name:     "first certificate",
+			previous: models.CertificateRecord{},
+			current:  func(info models.SSLInfo) models.SSLInfo { return info },
+		},
+		{
+			name:     "same certificate",
+			previous: previous,
+			current:  func(info models.SSLInfo) models.SSLInfo { info.Fingerprint = "aa"; return info },
+		},
+		{
+			name:     "renewed",
+			previous: previous,
+			current:  func(info models.SSLInfo) models.SSLInfo { return info },
+			want:     []string{"Renewed"},
+		},
+		{
+			name:     "new subject",
+			previous: previous,
+			current:  func(info models.SSLInfo) models.SSLInfo { info.Subject = "www.example.com"; return info },
+			want:     []string{"Subject changed from example.com to www.example.com"},
+		},
+		{
+			name:           "new issuer",
+			previous:       previous,
+			current:        func(info models.SSLInfo) models.SSLInfo { info.Issuer = "Evil CA"; return info },
+			want:           []string{"Issuer changed from R3 to Evil CA"},
+			wantUnexpected: true,
+		},
+		{
+			name:     "new key",
+			previous: previous,
+			current: func(info models.SSLInfo) models.SSLInfo {
+				info.KeyType, info.KeySize = "ECDSA", 256
+				return info
+			},
+			want:           []string{"Key changed from RSA 2048 to ECDSA 256"},
+			wantUnexpected: true,
+		},
+		{
+			name: "replaced early",
+			previous: func() models.CertificateRecord {
+				p := previous
+				p.ValidTo = checkedAt + 60*day
+				return p
+			}(),
+			current:        func(info models.SSLInfo) models.SSLInfo { return info },
+			want:           []string{"Replaced 60 days before it expired"},
+			wantUnexpected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, unexpected := CertificateChanges(tt.previous, tt.current(renewed))
+			if len(changes) != len(tt.want) {
+				t.Fatalf("changes = %q, want %q", changes, tt.want)
+			}
+			for i := range changes {
+				if !strings.HasPrefix(changes[i], tt.want[i]) {
+					t.Errorf("changes = %q, want %q", changes, tt.want)
+				}
+			}
+			if unexpected != tt.wantUnexpected {
+				t.Errorf("unexpected = %v, want %v", unexpected, tt.wantUnexpected)
+			}
+		})
+	}
+}
+
+func TestNewCertificateRecord(t *testing.T) {
+	old := models.CertificateRecord{Fingerprint: "aa", Issuer: "R3", LastSeen: 1700000000}
+	swapped := models.CertificateRecord{Fingerprint: "bb", Issuer: "R3", LastSeen: 1700003600}
+	history := []models.CertificateRecord{swapped, old}
+	if latest := LatestCertificate(history); latest == nil || latest.Fingerprint != "bb" {
+		t.Fatalf("LatestCertificate() = %+v, want the last served", latest)
+	}
+	if LatestCertificate(nil) != nil {
+		t.Errorf("LatestCertificate(nil) != nil")
+	}
+
+	// Swapping back to an older certificate is a change from the latest one
+	info := models.SSLInfo{WebsiteID: "w1", Fingerprint: "aa", Issuer: "R3", CheckedAt: 1700007200}
+	record := NewCertificateRecord(info, LatestCertificate(history))
+	if record.ID != "w1:aa" || record.PreviousFingerprint != "bb" || len(record.Changes) == 0 {
+		t.Errorf("NewCertificateRecord() = %+v, want it to replace bb", record)
+	}
+	if record := NewCertificateRecord(info, &old); record.PreviousFingerprint != "" || record.Changes != nil {
+		t.Errorf("NewCertificateRecord() of the same certificate = %+v, want no change", record)
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// MonitorType returns a website's monitor type, treating websites saved
+// before types existed as HTTP monitors
+func MonitorType(website models.Website) string {
+	if website.Type == "" {
+		return models.MonitorHTTP
+	}
+	return website.Type
+}
+
+// SplitTarget splits a non-HTTP monitor target ("host:port", optionally
+// prefixed with a scheme such as tcp://) into host and port, using
+// defaultPort when the target has none
+func SplitTarget(target, defaultPort string) (host, port string, err error) {
+	target = strings.TrimSpace(target)
+	if i := strings.Index(target, "://"); i != -1 {
+		target = target[i+3:]
+	}
+	target = strings.TrimRight(target, "/")
+	if target == "" {
+		return "", "", fmt.Errorf("empty target")
+	}
+
+	host, port, err = net.SplitHostPort(target)
+	if err != nil {
+		// No port given: the whole target is the host
+		if defaultPort == "" {
+			return "", "", fmt.Errorf("target %q must be host:port", target)
+		}
+		host, port = strings.Trim(target, "[]"), defaultPort
+	}
+	if host == "" {
+		return "", "", fmt.Errorf("target %q has no host", target)
+	}
+	return host, port, nil
+}
+
+// TargetHost returns the host a website's checks connect to
+func TargetHost(website models.Website) string {
+	switch website.Type {
+	case "", models.MonitorHTTP:
+		if u, err := url.Parse(website.URL); err == nil && u.Host != "" {
+			return strings.ToLower(u.Hostname())
+		}
+	default:
+		if host, _, err := SplitTarget(website.URL, "0"); err == nil {
+			return strings.ToLower(host)
+		}
+	}
+	return strings.ToLower(website.URL)
+}
+
+// checkTCP checks that a TCP connection to host:port can be opened
+func (s *MonitorService) checkTCP(website models.Website, status models.WebsiteStatus) (models.WebsiteStatus, error) {
+	host, port, err := SplitTarget(website.URL, "")
+	if err != nil {
+		return failedStatus(status, err)
+	}
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), CheckTimeout(website))
+	status.ResponseTime = time.Since(start).Milliseconds()
+	if err != nil {
+		return failedStatus(status, err)
+	}
+	conn.Close()
+
+	status.IsUp = true
+	return status, nil
+}
+
+// checkTLS checks that a TLS handshake succeeds and the certificate is
+// currently valid, reusing SSLService for the handshake
+func (s *MonitorService) checkTLS(website models.Website, status models.WebsiteStatus) (models.WebsiteStatus, error) {
+	start := time.Now()
+	info, err := s.ssl.CheckWebsite(website)
+	status.ResponseTime = time.Since(start).Milliseconds()
+	if err != nil {
+		return failedStatus(status, err)
+	}
+	if info.Error != "" {
+		return failedStatus(status, fmt.Errorf("%s", info.Error))
+	}
+
+	now := time.Now().Unix()
+	status.IsUp = now >= info.ValidFrom && now <= info.ValidTo
+	if !status.IsUp {
+		status.FailedAssertion = fmt.Sprintf("certificate is not valid now (valid %s to %s)",
+			time.Unix(info.ValidFrom, 0).UTC().Format(time.DateOnly), time.Unix(info.ValidTo, 0).UTC().Format(time.DateOnly))
+	} else if info.OCSPStatus == models.OCSPRevoked {
+		status.IsUp = false
+		status.FailedAssertion = "certificate was revoked"
+	}
+	return status, nil
+}
+
+// checkDNS resolves the target and, if an expected value is set, checks
+// that it is among the returned records
+func (s *MonitorService) checkDNS(website models.Website, status models.WebsiteStatus) (models.WebsiteStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), CheckTimeout(website))
+	defer cancel()
+
+	name, _, err := SplitTarget(website.URL, "53")
+	if err != nil {
+		return failedStatus(status, err)
+	}
+
+	start := time.Now()
+	records, err := lookupRecords(ctx, dnsResolver(website.DNSResolver), name, DNSRecordType(website))
+	status.ResponseTime = time.Since(start).Milliseconds()
+	if err != nil {
+		return failedStatus(status, err)
+	}
+
+	status.IsUp = len(records) > 0
+	if !status.IsUp {
+		status.FailedAssertion = fmt.Sprintf("no %s records found", DNSRecordType(website))
+	} else if expected := normalizeDNSValue(website.DNSExpectedValue); expected != "" {
+		found := false
+		for _, record := range records {
+			if normalizeDNSValue(record) == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			status.IsUp = false
+			status.FailedAssertion = fmt.Sprintf("%s records %v do not include %s",
+				DNSRecordType(website), records, website.DNSExpectedValue)
+		}
+	}
+	return status, nil
+}
+
+// DNSRecordType returns the record type a DNS monitor queries
+func DNSRecordType(website models.Website) string {
+	if website.DNSRecordType == "" {
+		return "A"
+	}
+	return strings.ToUpper(website.DNSRecordType)
+}
+
+// dnsResolver returns a resolver that queries addr, or the system resolver
+func dnsResolver(addr string) *net.Resolver {
+	if addr == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+}
+
+// lookupRecords returns the values of name's records of the given type
+func lookupRecords(ctx context.Context, resolver *net.Resolver, name, recordType string) ([]string, error) {
+	var records []string
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			records = append(records, ip.String())
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, cname)
+	case "MX":
+		mxs, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			records = append(records, mx.Host)
+		}
+	case "NS":
+		nss, err := resolver.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			records = append(records, ns.Host)
+		}
+	case "TXT":
+		txts, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, txts...)
+	default:
+		return nil, fmt.Errorf("unsupported DNS record type %q", recordType)
+	}
+	sort.Strings(records)
+	return records, nil
+}
+
+// normalizeDNSValue makes record values comparable regardless of case and
+// trailing dots
+func normalizeDNSValue(value string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), ".")
+}
+
+// failedStatus marks status as down because of err
+func failedStatus(status models.WebsiteStatus, err error) (models.WebsiteStatus, error) {
+	status.IsUp = false
+	status.StatusCode = 0
+	status.Error = err.Error()
+	return status, err
+}
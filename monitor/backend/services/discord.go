@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/prateeks007/PulseWatch/monitor/backend/models"
@@ -32,31 +33,54 @@ func (d *DiscordService) SendAlert(website models.Website, isUp bool, responseTi
 
 // SendAlertToWebhook sends alert to a specific webhook URL
 func (d *DiscordService) SendAlertToWebhook(webhookURL string, website models.Website, isUp bool, responseTime int64) error {
+	alertType := models.AlertDown
+	if isUp {
+		alertType = models.AlertUp
+	}
+	return d.SendAlertEmbed(webhookURL, Alert{
+		Website: website,
+		Type:    alertType,
+		Status:  models.WebsiteStatus{IsUp: isUp, ResponseTime: responseTime},
+		Time:    time.Now(),
+	})
+}
+
+// SendAlertEmbed sends an alert as a Discord embed to a specific webhook URL
+func (d *DiscordService) SendAlertEmbed(webhookURL string, alert Alert) error {
 	if webhookURL == "" {
 		return nil // Skip if no webhook configured
 	}
 
-	var color int
-	var status string
-	var emoji string
-
-	if isUp {
+	color := 0xff0000 // Red
+	if alert.IsUp() {
 		color = 0x00ff00 // Green
-		status = "ONLINE"
-		emoji = "✅"
-	} else {
-		color = 0xff0000 // Red
-		status = "OFFLINE"
-		emoji = "❌"
+	} else if alert.IsWarning() {
+		color = 0xffa500 // Orange
+	}
+
+	// A channel body template replaces the default field list
+	description := alert.body
+	if description == "" {
+		var fields strings.Builder
+		if summary := alert.Summary(); summary != "" {
+			fields.WriteString(summary)
+		}
+		for _, field := range alert.Fields() {
+			if fields.Len() > 0 {
+				fields.WriteString("\n")
+			}
+			fmt.Fprintf(&fields, "**%s:** %s", field.Label, field.Value)
+		}
+		description = fields.String()
 	}
 
 	payload := map[string]interface{}{
 		"embeds": []map[string]interface{}{
 			{
-				"title":       fmt.Sprintf("%s %s is %s", emoji, website.Name, status),
-				"description": fmt.Sprintf("**URL:** %s\n**Response Time:** %dms", website.URL, responseTime),
+				"title":       alert.Title(),
+				"description": description,
 				"color":       color,
-				"timestamp":   time.Now().Format(time.RFC3339),
+				"timestamp":   alert.Time.Format(time.RFC3339),
 			},
 		},
 	}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	defaultRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+	defaultWHOISServer      = "whois.iana.org:43"
+	// domainCheckInterval is how long a lookup is reused before asking again
+	domainCheckInterval = 20 * time.Hour
+	// rdapBootstrapTTL is how long the RDAP server list is cached
+	rdapBootstrapTTL = 24 * time.Hour
+	// maxDomainResponseSize bounds how much of an RDAP or WHOIS answer is read
+	maxDomainResponseSize = 1 << 20
+)
+
+// defaultDomainExpiryThresholds are the days before a registration expires
+// domains are alerted at when the user hasn't chosen their own
+var defaultDomainExpiryThresholds = []int{30, 14, 7, 1}
+
+// DomainConfig holds the registration lookup endpoints, so local stand-ins
+// can replace the public ones
+type DomainConfig struct {
+	RDAPBootstrapURL string // IANA bootstrap file mapping TLDs to RDAP servers
+	RDAPServer       string // RDAP base URL used for every domain, skipping the bootstrap
+	WHOISServer      string // host:port asked first; its "refer:" answer is followed
+}
+
+// DomainConfigFromEnv reads RDAP_BOOTSTRAP_URL, RDAP_SERVER_URL and
+// WHOIS_SERVER, falling back to IANA's bootstrap file and WHOIS server
+func DomainConfigFromEnv() DomainConfig {
+	cfg := DomainConfig{
+		RDAPBootstrapURL: os.Getenv("RDAP_BOOTSTRAP_URL"),
+		RDAPServer:       os.Getenv("RDAP_SERVER_URL"),
+		WHOISServer:      os.Getenv("WHOIS_SERVER"),
+	}
+	if cfg.RDAPBootstrapURL == "" {
+		cfg.RDAPBootstrapURL = defaultRDAPBootstrapURL
+	}
+	if cfg.WHOISServer == "" {
+		cfg.WHOISServer = defaultWHOISServer
+	}
+	return cfg
+}
+
+// DomainService looks up domain registrations over RDAP, falling back to
+// WHOIS, and stores them per apex domain
+type DomainService struct {
+	storage Store
+	cfg     DomainConfig
+	client  *http.Client
+
+	mu          sync.Mutex
+	rdapServers map[string]string // TLD to RDAP base URL
+	fetchedAt   time.Time
+}
+
+// NewDomainService creates a domain service using the given endpoints
+func NewDomainService(storage Store, cfg DomainConfig) *DomainService {
+	return &DomainService{
+		storage: storage,
+		cfg:     cfg,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// ApexDomain returns the registrable domain a website's target is under,
+// e.g. example.co.uk for https://www.example.co.uk
+func ApexDomain(website models.Website) (string, error) {
+	host := TargetHost(website)
+	if host == "" || net.ParseIP(host) != nil {
+		return "", fmt.Errorf("%q has no domain", host)
+	}
+	return publicsuffix.EffectiveTLDPlusOne(strings.TrimSuffix(host, "."))
+}
+
+// Check returns the registration of a domain, from storage if it was
+// looked up recently. Failed lookups are logged and reported in Error;
+// the returned error is only set if the result couldn't be saved.
+func (d *DomainService) Check(domain string) (*models.DomainInfo, error) {
+	now := time.Now()
+	cached, err := d.storage.GetDomain(domain)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to get stored domain %s: %v\n", domain, err)
+	}
+	if cached != nil && now.Sub(time.Unix(cached.CheckedAt, 0)) < domainCheckInterval {
+		return cached, nil
+	}
+
+	info := d.Lookup(domain, now)
+	if info.Error != "" {
+		fmt.Printf("⚠️ Domain lookup for %s failed: %s\n", domain, info.Error)
+		if cached != nil && cached.ExpiresAt != 0 {
+			// Keep what the last successful lookup found
+			info.Registrar, info.ExpiresAt, info.Source = cached.Registrar, cached.ExpiresAt, cached.Source
+			info.DaysLeft = int(time.Unix(info.ExpiresAt, 0).Sub(now).Hours() / 24)
+		}
+	}
+	if err := d.storage.SaveDomain(*info); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+// Lookup queries a domain's registration, over RDAP if its TLD has a
+// server and WHOIS otherwise. Failures are reported in Error.
+func (d *DomainService) Lookup(domain string, now time.Time) *models.DomainInfo {
+	info, rdapErr := d.lookupRDAP(domain)
+	if rdapErr != nil {
+		var whoisErr error
+		if info, whoisErr = d.lookupWHOIS(domain); whoisErr != nil {
+			info = &models.DomainInfo{Error: fmt.Sprintf("rdap: %v; whois: %v", rdapErr, whoisErr)}
+		}
+	}
+	info.Domain = domain
+	info.CheckedAt = now.Unix()
+	if info.ExpiresAt != 0 {
+		info.DaysLeft = int(time.Unix(info.ExpiresAt, 0).Sub(now).Hours() / 24)
+	}
+	return info
+}
+
+// rdapDomain is the part of an RDAP domain response we read
+type rdapDomain struct {
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string          `json:"roles"`
+		VCardArray []json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// lookupRDAP queries the domain's RDAP server
+func (d *DomainService) lookupRDAP(domain string) (*models.DomainInfo, error) {
+	base, err := d.rdapServer(domain)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", strings.TrimRight(base, "/")+"/domain/"+domain, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+	req.Header.Set("User-Agent", "PulseWatch-Monitor/1.0")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var result rdapDomain
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDomainResponseSize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	info := &models.DomainInfo{Source: "rdap"}
+	for _, event := range result.Events {
+		if event.Action == "expiration" {
+			if t, err := time.Parse(time.RFC3339, event.Date); err == nil {
+				info.ExpiresAt = t.Unix()
+			}
+		}
+	}
+	if info.ExpiresAt == 0 {
+		return nil, fmt.Errorf("no expiration event")
+	}
+	for _, entity := range result.Entities {
+		for _, role := range entity.Roles {
+			if role == "registrar" {
+				info.Registrar = vcardName(entity.VCardArray)
+			}
+		}
+	}
+	return info, nil
+}
+
+// rdapServer returns the RDAP base URL for a domain's TLD
+func (d *DomainService) rdapServer(domain string) (string, error) {
+	if d.cfg.RDAPServer != "" {
+		return d.cfg.RDAPServer, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.rdapServers == nil || time.Since(d.fetchedAt) > rdapBootstrapTTL {
+		servers, err := d.fetchRDAPBootstrap()
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch RDAP bootstrap: %w", err)
+		}
+		d.rdapServers, d.fetchedAt = servers, time.Now()
+	}
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	if server := d.rdapServers[tld]; server != "" {
+		return server, nil
+	}
+	return "", fmt.Errorf("no RDAP server for .%s", tld)
+}
+
+// fetchRDAPBootstrap reads IANA's bootstrap file, in which each service
+// pairs a list of TLDs with a list of server URLs
+func (d *DomainService) fetchRDAPBootstrap() (map[string]string, error) {
+	resp, err := d.client.Get(d.cfg.RDAPBootstrapURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var bootstrap struct {
+		Services [][][]string `json:"services"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDomainResponseSize)).Decode(&bootstrap); err != nil {
+		return nil, err
+	}
+	servers := make(map[string]string)
+	for _, service := range bootstrap.Services {
+		if len(service) != 2 || len(service[1]) == 0 {
+			continue
+		}
+		// Prefer an HTTPS server
+		server := service[1][0]
+		for _, candidate := range service[1] {
+			if strings.HasPrefix(candidate, "https://") {
+				server = candidate
+				break
+			}
+		}
+		for _, tld := range service[0] {
+			servers[strings.ToLower(tld)] = server
+		}
+	}
+	return servers, nil
+}
+
+// vcardName returns the "fn" (formatted name) of a jCard, e.g.
+// ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Name"]]]
+func vcardName(vcard []json.RawMessage) string {
+	if len(vcard) < 2 {
+		return ""
+	}
+	var properties [][]interface{}
+	if err := json.Unmarshal(vcard[1], &properties); err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if len(property) >= 4 && property[0] == "fn" {
+			if name, ok := property[3].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// lookupWHOIS asks the configured WHOIS server, following its referral
+// to the TLD's server as whois.iana.org answers
+func (d *DomainService) lookupWHOIS(domain string) (*models.DomainInfo, error) {
+	server := d.cfg.WHOISServer
+	for hops := 0; hops < 3; hops++ {
+		response, err := queryWHOIS(server, domain)
+		if err != nil {
+			return nil, err
+		}
+		fields := parseWHOIS(response)
+		if refer := fields["refer"]; refer != "" && !strings.EqualFold(refer, strings.Split(server, ":")[0]) {
+			server = net.JoinHostPort(refer, "43")
+			continue
+		}
+
+		info := &models.DomainInfo{Source: "whois", Registrar: fields["registrar"]}
+		for _, key := range whoisExpiryKeys {
+			if value := fields[key]; value != "" {
+				if t, ok := parseWHOISDate(value); ok {
+					info.ExpiresAt = t.Unix()
+					break
+				}
+			}
+		}
+		if info.ExpiresAt == 0 {
+			return nil, fmt.Errorf("no expiry date in response from %s", server)
+		}
+		return info, nil
+	}
+	return nil, fmt.Errorf("too many referrals")
+}
+
+// queryWHOIS sends a query to a WHOIS server and reads its whole answer
+func queryWHOIS(server, query string) (string, error) {
+	conn, err := net.DialTimeout("tcp", server, 10*time.Second)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(15 * time.Second))
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", err
+	}
+	response, err := io.ReadAll(io.LimitReader(conn, maxDomainResponseSize))
+	if err != nil {
+		return "", err
+	}
+	return string(response), nil
+}
+
+// whoisExpiryKeys are the labels registries use for the expiry date, lower case
+var whoisExpiryKeys = []string{
+	"registry expiry date",
+	"registrar registration expiration date",
+	"expiry date",
+	"expiration date",
+	"expiration time",
+	"expires on",
+	"expires",
+	"paid-till",
+	"renewal date",
+}
+
+// parseWHOIS returns the "key: value" lines of a WHOIS answer, keyed in
+// lower case. The first value of a repeated key wins.
+func parseWHOIS(response string) map[string]string {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if _, seen := fields[key]; !seen && value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// whoisDateLayouts are the date formats seen in WHOIS answers
+var whoisDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02",
+	"2006.01.02",
+	"2006/01/02",
+	"02-Jan-2006",
+	"02.01.2006",
+	"January 2 2006",
+}
+
+// parseWHOISDate parses a WHOIS date in any of the known layouts
+func parseWHOISDate(value string) (time.Time, bool) {
+	// Some registries append a zone name or comment after the date
+	candidates := []string{value}
+	if fields := strings.Fields(value); len(fields) > 1 {
+		candidates = append(candidates, fields[0])
+	}
+	for _, candidate := range candidates {
+		for _, layout := range whoisDateLayouts {
+			if t, err := time.Parse(layout, candidate); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// DomainExpiryThresholds returns the user's domain expiry thresholds in
+// days, largest first
+func DomainExpiryThresholds(user *models.User) []int {
+	if user == nil || len(user.DomainExpiryThresholds) == 0 {
+		return defaultDomainExpiryThresholds
+	}
+	return sortedThresholds(user.DomainExpiryThresholds)
+}
+
+// DomainStateID returns the ID of the monitor state domain alerts for a
+// user's domain are tracked under, so websites sharing an apex domain
+// alert once between them
+func DomainStateID(userID, domain string) string {
+	return "domain:" + userID + ":" + domain
+}
+
+// ApplyDomainResult records a domain lookup on a monitor's state and
+// returns AlertDomainExpiring once per threshold crossed, or "". Lookups
+// that never found an expiry date are ignored, since registries often rate
+// limit or omit it. A renewed registration starts over.
+func ApplyDomainResult(state *models.MonitorState, info models.DomainInfo, thresholds []int) string {
+	if info.ExpiresAt == 0 {
+		return ""
+	}
+	if info.ExpiresAt != state.DomainExpiresAt {
+		state.DomainExpiresAt = info.ExpiresAt
+		state.DomainAlertedThreshold = 0
+	}
+	crossed := crossedThreshold(thresholds, info.DaysLeft, state.DomainAlertedThreshold)
+	if crossed == 0 {
+		return ""
+	}
+	state.DomainAlertedThreshold = crossed
+	return models.AlertDomainExpiring
+}
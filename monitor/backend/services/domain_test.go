@@ -0,0 +1,297 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+const rdapExample = `{
+	"events": [
+		{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+		{"eventAction": "expiration", "eventDate": "2030-08-13T04:00:00Z"}
+	],
+	"entities": [
+		{"roles": ["registrar"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]}
+	]
+}`
+
+// newRDAPServer starts an HTTPS RDAP stand-in that also serves a bootstrap
+// file listing it after a plain HTTP server for "com" and "org"
+func newRDAPServer(t *testing.T, domains map[string]string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dns.json" {
+			fmt.Fprintf(w, `{"services": [[["net"], ["http://rdap.invalid/"]], [["com", "org"], ["http://rdap.invalid/", %q]]]}`, server.URL+"/")
+			return
+		}
+		body, ok := domains[strings.TrimPrefix(r.URL.Path, "/domain/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newWHOISServer starts a WHOIS stand-in answering each query from answers
+func newWHOISServer(t *testing.T, answers map[string]string) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			query, _ := bufio.NewReader(conn).ReadString('\n')
+			fmt.Fprint(conn, answers[strings.TrimSpace(query)])
+			conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestLookupRDAP(t *testing.T) {
+	server := newRDAPServer(t, map[string]string{
+		"example.com":   rdapExample,
+		"noexpiry.com":  `{"events": [{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"}]}`,
+		"broken.com":    `{"events": [`,
+		"example.org":   rdapExample,
+		"badformat.com": `{"events": [{"eventAction": "expiration", "eventDate": "13 Aug 2030"}]}`,
+	})
+	tests := []struct {
+		name      string
+		domain    string
+		server    string // RDAPServer override, skipping the bootstrap
+		want      int64
+		registrar string
+		wantErr   string
+	}{
+		{name: "bootstrap", domain: "example.com", want: 1912824000, registrar: "Example Registrar, Inc."},
+		{name: "second TLD of a service", domain: "example.org", want: 1912824000, registrar: "Example Registrar, Inc."},
+		{name: "server override", domain: "example.com", server: server.URL, want: 1912824000, registrar: "Example Registrar, Inc."},
+		{name: "no expiration event", domain: "noexpiry.com", wantErr: "no expiration event"},
+		{name: "unparseable date", domain: "badformat.com", wantErr: "no expiration event"},
+		{name: "invalid JSON", domain: "broken.com", wantErr: "invalid response"},
+		{name: "not found", domain: "missing.com", wantErr: "HTTP 404"},
+		{name: "TLD without RDAP", domain: "example.io", wantErr: "no RDAP server for .io"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDomainService(nil, DomainConfig{RDAPBootstrapURL: server.URL + "/dns.json", RDAPServer: tt.server})
+			d.client = server.Client()
+			info, err := d.lookupRDAP(tt.domain)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("lookupRDAP() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupRDAP() error = %v", err)
+			}
+			if info.ExpiresAt != tt.want || info.Registrar != tt.registrar || info.Source != "rdap" {
+				t.Errorf("lookupRDAP() = %+v, want expiry %d from %q", info, tt.want, tt.registrar)
+			}
+		})
+	}
+}
+
+func TestLookupRDAPBootstrapUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	d := NewDomainService(nil, DomainConfig{RDAPBootstrapURL: server.URL})
+	if _, err := d.lookupRDAP("example.com"); err == nil || !strings.Contains(err.Error(), "failed to fetch RDAP bootstrap") {
+		t.Errorf("lookupRDAP() error = %v, want a bootstrap failure", err)
+	}
+}
+
+func TestLookupWHOIS(t *testing.T) {
+	addr := newWHOISServer(t, map[string]string{
+		"example.com": "% IANA WHOIS server\r\n" +
+			"Domain Name: EXAMPLE.COM\r\n" +
+			"Registrar: Example Registrar, Inc.\r\n" +
+			"Registry Expiry Date: 2030-08-13T04:00:00Z\r\n" +
+			"Registrar Registration Expiration Date: 2031-01-01T00:00:00Z\r\n",
+		"example.ru": "domain: EXAMPLE.RU\nregistrar: RU-CENTER-RU\npaid-till: 2030-08-13T04:00:00Z\n",
+		"example.uk": "    Registrar:\n        Example Ltd\n    Expiry date:  13-Aug-2030\n",
+		// whois.iana.org answers with the TLD's server; one naming the
+		// server itself isn't followed again
+		"self.com":    "refer: 127.0.0.1\nexpires: 2030-08-13\n",
+		"missing.com": "No match for \"MISSING.COM\".\n",
+	})
+	tests := []struct {
+		domain    string
+		want      int64
+		registrar string
+		wantErr   string
+	}{
+		{domain: "example.com", want: 1912824000, registrar: "Example Registrar, Inc."},
+		{domain: "example.ru", want: 1912824000, registrar: "RU-CENTER-RU"},
+		{domain: "example.uk", want: 1912809600},
+		{domain: "self.com", want: 1912809600},
+		{domain: "missing.com", wantErr: "no expiry date in response from " + addr},
+	}
+
+	d := NewDomainService(nil, DomainConfig{WHOISServer: addr})
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			info, err := d.lookupWHOIS(tt.domain)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("lookupWHOIS() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupWHOIS() error = %v", err)
+			}
+			if info.ExpiresAt != tt.want || info.Registrar != tt.registrar || info.Source != "whois" {
+				t.Errorf("lookupWHOIS() = %+v, want expiry %d from %q", info, tt.want, tt.registrar)
+			}
+		})
+	}
+}
+
+func TestLookupFallsBackToWHOIS(t *testing.T) {
+	rdap := newRDAPServer(t, nil)
+	whois := newWHOISServer(t, map[string]string{"example.com": "Registry Expiry Date: 2030-08-13T04:00:00Z\n"})
+	now := time.Date(2030, 7, 14, 4, 0, 0, 0, time.UTC)
+
+	d := NewDomainService(nil, DomainConfig{RDAPBootstrapURL: rdap.URL + "/dns.json", WHOISServer: whois})
+	d.client = rdap.Client()
+	info := d.Lookup("example.com", now)
+	if info.Error != "" || info.Source != "whois" || info.DaysLeft != 30 || info.CheckedAt != now.Unix() {
+		t.Errorf("Lookup() = %+v, want 30 days left from WHOIS", info)
+	}
+
+	info = d.Lookup("missing.com", now)
+	if !strings.HasPrefix(info.Error, "rdap: HTTP 404; whois: no expiry date") || info.Domain != "missing.com" {
+		t.Errorf("Lookup() = %+v, want both failures reported", info)
+	}
+}
+
+func TestParseWHOISDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string // RFC 3339, empty if it shouldn't parse
+	}{
+		{"2030-08-13T04:00:00Z", "2030-08-13T04:00:00Z"},
+		{"2030-08-13T04:00:00+02:00", "2030-08-13T04:00:00+02:00"},
+		{"2030-08-13T04:00:00", "2030-08-13T04:00:00Z"},
+		{"2030-08-13 04:00:00", "2030-08-13T04:00:00Z"},
+		{"2030-08-13", "2030-08-13T00:00:00Z"},
+		{"2030.08.13", "2030-08-13T00:00:00Z"},
+		{"2030/08/13", "2030-08-13T00:00:00Z"},
+		{"13-Aug-2030", "2030-08-13T00:00:00Z"},
+		{"13.08.2030", "2030-08-13T00:00:00Z"},
+		{"August 13 2030", "2030-08-13T00:00:00Z"},
+		{"2030-08-13 (YYYY-MM-DD)", "2030-08-13T00:00:00Z"},
+		{"soon", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, ok := parseWHOISDate(tt.value)
+		if tt.want == "" {
+			if ok {
+				t.Errorf("parseWHOISDate(%q) = %v, want no date", tt.value, got)
+			}
+			continue
+		}
+		want, _ := time.Parse(time.RFC3339, tt.want)
+		if !ok || !got.Equal(want) {
+			t.Errorf("parseWHOISDate(%q) = %v, %v; want %v", tt.value, got, ok, want)
+		}
+	}
+}
+
+func TestApexDomain(t *testing.T) {
+	tests := []struct {
+		website models.Website
+		want    string
+	}{
+		{models.Website{URL: "https://www.example.co.uk/status"}, "example.co.uk"},
+		{models.Website{URL: "https://api.eu.example.com"}, "example.com"},
+		{models.Website{Type: models.MonitorTLS, URL: "mail.example.org:993"}, "example.org"},
+		{models.Website{URL: "https://127.0.0.1:8443"}, ""},
+	}
+	for _, tt := range tests {
+		got, err := ApexDomain(tt.website)
+		if got != tt.want || (tt.want != "" && err != nil) {
+			t.Errorf("ApexDomain(%q) = %q, %v; want %q", tt.website.URL, got, err, tt.want)
+		}
+	}
+}
+
+func TestApplyDomainResult(t *testing.T) {
+	const expiresAt, renewedAt = 1912824000, 1944360000
+	lookup := func(daysLeft int) models.DomainInfo {
+		return models.DomainInfo{ExpiresAt: expiresAt, DaysLeft: daysLeft}
+	}
+	tests := []struct {
+		name    string
+		lookups []models.DomainInfo
+		want    []string
+	}{
+		{
+			name:    "each threshold alerts once",
+			lookups: []models.DomainInfo{lookup(45), lookup(30), lookup(20), lookup(14), lookup(1)},
+			want:    []string{"", models.AlertDomainExpiring, "", models.AlertDomainExpiring, models.AlertDomainExpiring},
+		},
+		{
+			name:    "failed lookups are ignored",
+			lookups: []models.DomainInfo{lookup(10), {Error: "rate limited"}, lookup(9)},
+			want:    []string{models.AlertDomainExpiring, "", ""},
+		},
+		{
+			name:    "renewal starts over",
+			lookups: []models.DomainInfo{lookup(5), {ExpiresAt: renewedAt, DaysLeft: 370}, {ExpiresAt: renewedAt, DaysLeft: 29}},
+			want:    []string{models.AlertDomainExpiring, "", models.AlertDomainExpiring},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &models.MonitorState{}
+			var got []string
+			for _, info := range tt.lookups {
+				got = append(got, ApplyDomainResult(state, info, DomainExpiryThresholds(nil)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alerts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainExpiryThresholds(t *testing.T) {
+	if got := DomainExpiryThresholds(nil); !reflect.DeepEqual(got, defaultDomainExpiryThresholds) {
+		t.Errorf("DomainExpiryThresholds(nil) = %v, want the defaults", got)
+	}
+	// Certificate settings don't apply to domains
+	user := &models.User{SSLExpiryThresholds: []int{5}, DomainExpiryThresholds: []int{7, 60}}
+	if got, want := DomainExpiryThresholds(user), []int{60, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DomainExpiryThresholds() = %v, want %v", got, want)
+	}
+	if DomainStateID("u1", "example.com") == DomainStateID("u2", "example.com") {
+		t.Error("DomainStateID() is shared between users")
+	}
+}
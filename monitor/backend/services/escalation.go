@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// EscalationService notifies escalation policy steps and reminders for
+// open incidents. It works from the persisted incident, so it survives
+// restarts and runs safely on several instances.
+type EscalationService struct {
+	storage       Store
+	notifications *NotificationService
+	ackLinks      *AckLinkService
+	maintenance   *MaintenanceService
+}
+
+// NewEscalationService creates an escalation service. Nothing is sent for
+// websites in a maintenance window or while they flap.
+func NewEscalationService(storage Store, notifications *NotificationService, ackLinks *AckLinkService, maintenance *MaintenanceService) *EscalationService {
+	return &EscalationService{storage: storage, notifications: notifications, ackLinks: ackLinks, maintenance: maintenance}
+}
+
+// FindEscalationPolicy returns the user's policy with the given ID, or nil
+func FindEscalationPolicy(user *models.User, id string) *models.EscalationPolicy {
+	if user == nil || id == "" {
+		return nil
+	}
+	for i := range user.EscalationPolicies {
+		if user.EscalationPolicies[i].ID == id {
+			return &user.EscalationPolicies[i]
+		}
+	}
+	return nil
+}
+
+// PolicyChannels returns the user's channels used by the first steps of a policy
+func PolicyChannels(user *models.User, policy models.EscalationPolicy, steps int) []models.NotificationChannel {
+	if steps > len(policy.Steps) {
+		steps = len(policy.Steps)
+	}
+	wanted := make(map[string]bool)
+	for _, step := range policy.Steps[:steps] {
+		for _, id := range step.ChannelIDs {
+			wanted[id] = true
+		}
+	}
+
+	var channels []models.NotificationChannel
+	for _, channel := range UserChannels(user) {
+		if wanted[channel.ID] {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// AlertChannels returns where a website's alerts that aren't tied to an
+// incident go: the first step of its escalation policy, or every channel
+func AlertChannels(user *models.User, website models.Website) []models.NotificationChannel {
+	if policy := FindEscalationPolicy(user, website.EscalationPolicyID); policy != nil {
+		return PolicyChannels(user, *policy, 1)
+	}
+	return UserChannels(user)
+}
+
+// NextEscalation returns what a policy calls for on an incident at now:
+// notifying the next step, or reminding the steps already notified.
+// due is false if nothing is due yet.
+func NextEscalation(incident models.Incident, policy models.EscalationPolicy, now time.Time) (reminder, due bool) {
+	if incident.EscalationStep < len(policy.Steps) {
+		step := policy.Steps[incident.EscalationStep]
+		if now.Unix() >= incident.StartedAt+int64(step.AfterMinutes)*60 {
+			return false, true
+		}
+	}
+	if incident.EscalationStep > 0 && policy.RepeatMinutes > 0 &&
+		now.Unix() >= incident.LastNotifiedAt+int64(policy.RepeatMinutes)*60 {
+		return true, true
+	}
+	return false, false
+}
+
+// Escalate sends whatever an incident's policy calls for at now, if
+// anything. status is the check result to report, or nil for the
+// website's latest one.
+func (e *EscalationService) Escalate(incident models.Incident, website models.Website, user *models.User, status *models.WebsiteStatus, now time.Time) error {
+	if incident.Acknowledged() || incident.Status != models.IncidentOpen {
+		return nil
+	}
+	policy := FindEscalationPolicy(user, incident.EscalationPolicyID)
+	if policy == nil {
+		return nil
+	}
+	reminder, due := NextEscalation(incident, *policy, now)
+	if !due {
+		return nil
+	}
+
+	// Claim the notification before sending so no other instance sends it too
+	var channels []models.NotificationChannel
+	step := incident.EscalationStep
+	var event *models.IncidentEvent
+	if reminder {
+		channels = PolicyChannels(user, *policy, step)
+	} else {
+		channels = PolicyChannels(user, models.EscalationPolicy{Steps: policy.Steps[step : step+1]}, 1)
+		step++
+		names := make([]string, 0, len(channels))
+		for _, channel := range channels {
+			names = append(names, channel.Name)
+		}
+		event = &models.IncidentEvent{
+			Type:    models.EventEscalated,
+			At:      now.Unix(),
+			Message: fmt.Sprintf("Step %d of %s notified: %s", step, policy.Name, strings.Join(names, ", ")),
+		}
+	}
+	claimed, err := e.storage.ClaimEscalation(incident, step, now.Unix(), event)
+	if err != nil || !claimed {
+		return err
+	}
+	incident.EscalationStep = step
+	incident.LastNotifiedAt = now.Unix()
+
+	if status == nil {
+		if status, err = e.storage.GetLatestStatus(website.ID); err != nil {
+			return err
+		}
+		if status == nil {
+			status = &models.WebsiteStatus{WebsiteID: website.ID}
+		}
+	}
+	return e.notifications.Notify(channels, Alert{
+		Website:  website,
+		Type:     models.AlertDown,
+		Status:   *status,
+		Time:     now,
+		Incident: &incident,
+		AckURL:   e.ackLinks.URL(incident.ID),
+		Reminder: reminder,
+	})
+}
+
+// Run escalates every open, unacknowledged incident that follows a policy
+func (e *EscalationService) Run() {
+	incidents, err := e.storage.GetEscalatingIncidents()
+	if err != nil {
+		fmt.Printf("⚠️ Failed to get incidents for escalation: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	for _, incident := range incidents {
+		website, err := e.storage.GetWebsiteByUser(incident.WebsiteID, incident.UserID)
+		if err != nil {
+			continue // Website was deleted
+		}
+		if window, err := e.maintenance.Active(*website, now); err != nil || window != nil {
+			continue
+		}
+		// Flapping alerts are damped, so don't keep paging about the incident either
+		if state, err := e.storage.GetMonitorState(website.ID); err != nil || (state != nil && state.Flapping) {
+			continue
+		}
+		user, err := e.storage.GetUser(incident.UserID)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to get user for escalation of %s: %v\n", incident.WebsiteName, err)
+			continue
+		}
+		if err := e.Escalate(incident, *website, user, nil, now); err != nil {
+			fmt.Printf("⚠️ Failed to escalate incident %s: %v\n", incident.ID, err)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestNextEscalation(t *testing.T) {
+	const started = 1700000000
+	policy := models.EscalationPolicy{
+		Steps: []models.EscalationStep{
+			{AfterMinutes: 0, ChannelIDs: []string{"slack"}},
+			{AfterMinutes: 15, ChannelIDs: []string{"email"}},
+		},
+		RepeatMinutes: 30,
+	}
+	at := func(minutes int) time.Time { return time.Unix(started+int64(minutes)*60, 0) }
+
+	tests := []struct {
+		name         string
+		step         int   // Steps notified so far
+		lastNotified int64 // Minutes after the start of the last notification
+		policy       models.EscalationPolicy
+		now          time.Time
+		wantReminder bool
+		wantDue      bool
+	}{
+		{name: "first step right away", step: 0, policy: policy, now: at(0), wantDue: true},
+		{name: "second step not due yet", step: 1, lastNotified: 0, policy: policy, now: at(14)},
+		{name: "second step due", step: 1, lastNotified: 0, policy: policy, now: at(15), wantDue: true},
+		{name: "no reminder before the repeat", step: 2, lastNotified: 15, policy: policy, now: at(44)},
+		{name: "reminder after the repeat", step: 2, lastNotified: 15, policy: policy, now: at(45), wantReminder: true, wantDue: true},
+		{
+			name:         "reminder while a later step waits",
+			step:         1,
+			lastNotified: 0,
+			policy: models.EscalationPolicy{
+				Steps:         []models.EscalationStep{{AfterMinutes: 0}, {AfterMinutes: 60}},
+				RepeatMinutes: 10,
+			},
+			now:          at(10),
+			wantReminder: true,
+			wantDue:      true,
+		},
+		{
+			name:         "no reminders without a repeat",
+			step:         2,
+			lastNotified: 15,
+			policy:       models.EscalationPolicy{Steps: policy.Steps},
+			now:          at(600),
+		},
+		{name: "no steps", policy: models.EscalationPolicy{RepeatMinutes: 5}, now: at(600)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incident := models.Incident{
+				StartedAt:      started,
+				EscalationStep: tt.step,
+				LastNotifiedAt: started + tt.lastNotified*60,
+			}
+			reminder, due := NextEscalation(incident, tt.policy, tt.now)
+			if reminder != tt.wantReminder || due != tt.wantDue {
+				t.Errorf("NextEscalation() = reminder %v, due %v; want %v, %v", reminder, due, tt.wantReminder, tt.wantDue)
+			}
+		})
+	}
+}
+
+func TestPolicyChannels(t *testing.T) {
+	user := &models.User{
+		DiscordWebhookURL: "https://discord.com/api/webhooks/1/x",
+		NotificationChannels: []models.NotificationChannel{
+			{ID: "slack", Name: "Slack"},
+			{ID: "email", Name: "Email"},
+			{ID: "pager", Name: "Pager"},
+		},
+		EscalationPolicies: []models.EscalationPolicy{{
+			ID: "p1",
+			Steps: []models.EscalationStep{
+				{ChannelIDs: []string{"slack", "discord"}},
+				{AfterMinutes: 10, ChannelIDs: []string{"email", "slack"}},
+			},
+		}},
+	}
+	names := func(channels []models.NotificationChannel) []string {
+		var names []string
+		for _, channel := range channels {
+			names = append(names, channel.Name)
+		}
+		return names
+	}
+	policy := *FindEscalationPolicy(user, "p1")
+
+	tests := []struct {
+		name     string
+		channels []models.NotificationChannel
+		want     []string
+	}{
+		{"first step", PolicyChannels(user, policy, 1), []string{"Discord", "Slack"}},
+		{"both steps", PolicyChannels(user, policy, 2), []string{"Discord", "Slack", "Email"}},
+		{"past the last step", PolicyChannels(user, policy, 5), []string{"Discord", "Slack", "Email"}},
+		{"alerts of a website with the policy", AlertChannels(user, models.Website{EscalationPolicyID: "p1"}), []string{"Discord", "Slack"}},
+		{"alerts of a website without one", AlertChannels(user, models.Website{}), []string{"Discord", "Slack", "Email", "Pager"}},
+		{"alerts of a website with a deleted policy", AlertChannels(user, models.Website{EscalationPolicyID: "gone"}), []string{"Discord", "Slack", "Email", "Pager"}},
+	}
+	for _, tt := range tests {
+		if got := names(tt.channels); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: channels = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if FindEscalationPolicy(nil, "p1") != nil || FindEscalationPolicy(user, "") != nil {
+		t.Errorf("FindEscalationPolicy() found a policy without a user or ID")
+	}
+}
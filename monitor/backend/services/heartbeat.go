@@ -0,0 +1,148 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// defaultGracePeriod is how late a ping may be when a push monitor has no grace period set
+const defaultGracePeriod = 60 * time.Second
+
+// HeartbeatService tracks pings from push monitors and detects missed ones
+type HeartbeatService struct {
+	storage Store
+}
+
+// NewHeartbeatService creates a heartbeat service backed by storage
+func NewHeartbeatService(storage Store) *HeartbeatService {
+	return &HeartbeatService{storage: storage}
+}
+
+// GeneratePushToken returns a new random token for a push monitor's ping URL
+func GeneratePushToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate push token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// PushPath returns the path a push monitor's jobs ping
+func PushPath(token string) string {
+	return "/api/push/" + token
+}
+
+// GracePeriod returns how late a ping may be before a push monitor is down
+func GracePeriod(website models.Website) time.Duration {
+	if website.GracePeriod <= 0 {
+		return defaultGracePeriod
+	}
+	return time.Duration(website.GracePeriod) * time.Second
+}
+
+// RecordPing records a ping for a push monitor. A start signal only marks
+// the job as running and returns nil; success and fail signals return the
+// status to process like any other check result. durationMs is the job's
+// runtime if the job reported it, otherwise it is measured from the last start.
+func (h *HeartbeatService) RecordPing(website models.Website, signal string, durationMs int64, message string) (*models.WebsiteStatus, error) {
+	now := time.Now()
+	var startedAtMs int64
+	_, err := h.storage.UpdateMonitorState(website.ID, func(state *models.MonitorState) {
+		startedAtMs = state.LastStartAtMs
+		if signal == models.PingStart {
+			state.LastStartAtMs = now.UnixMilli()
+			return
+		}
+		state.LastPingAt = now.Unix()
+		state.LastStartAtMs = 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	if signal == models.PingStart {
+		return nil, nil
+	}
+
+	if durationMs <= 0 && startedAtMs > 0 {
+		durationMs = now.UnixMilli() - startedAtMs
+	}
+	status := &models.WebsiteStatus{
+		WebsiteID:     website.ID,
+		IsUp:          signal != models.PingFail,
+		JobDurationMs: durationMs,
+		CheckedAt:     now.Unix(),
+		Attempts:      1,
+	}
+	if !status.IsUp {
+		status.FailedAssertion = "job reported failure"
+		if message != "" {
+			status.FailedAssertion += ": " + message
+		}
+	}
+	return status, nil
+}
+
+// Evaluate returns a down status if a push monitor's ping is overdue, or
+// nil if the monitor is still within its period and grace window. Each
+// missed period is reported once, so a silent job records one failed
+// check per interval rather than one per evaluation.
+func (h *HeartbeatService) Evaluate(website models.Website) (*models.WebsiteStatus, error) {
+	state, err := h.storage.GetMonitorState(website.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Before the first ping the period counts from when the monitor was created
+	last := website.CreatedAt
+	if state != nil && state.LastPingAt > 0 {
+		last = state.LastPingAt
+	}
+	if last == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	deadline := MissedDeadline(website, last, now)
+	if deadline == 0 || (state != nil && state.MissedDeadline >= deadline) {
+		return nil, nil
+	}
+
+	// Claim the deadline so overlapping evaluations report it once
+	claimed := false
+	_, err = h.storage.UpdateMonitorState(website.ID, func(state *models.MonitorState) {
+		claimed = state.MissedDeadline < deadline
+		if claimed {
+			state.MissedDeadline = deadline
+		}
+	})
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	return &models.WebsiteStatus{
+		WebsiteID: website.ID,
+		IsUp:      false,
+		CheckedAt: now.Unix(),
+		Attempts:  1,
+		FailedAssertion: fmt.Sprintf("no ping received since %s (expected every %s + %s grace)",
+			time.Unix(last, 0).UTC().Format(time.RFC1123), CheckInterval(website), GracePeriod(website)),
+	}, nil
+}
+
+// MissedDeadline returns the latest ping deadline that has passed by now
+// for a push monitor last pinged at last (Unix time), or 0 if none has.
+// The first deadline is one interval plus the grace period after the ping,
+// and every interval after it is another.
+func MissedDeadline(website models.Website, last int64, now time.Time) int64 {
+	interval := CheckInterval(website)
+	first := time.Unix(last, 0).Add(interval + GracePeriod(website))
+	if now.Before(first) {
+		return 0
+	}
+	missed := now.Sub(first) / interval
+	return first.Add(missed * interval).Unix()
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IncidentService opens and resolves incidents as websites go down and recover
+type IncidentService struct {
+	storage Store
+}
+
+// NewIncidentService creates an incident service backed by storage
+func NewIncidentService(storage Store) *IncidentService {
+	return &IncidentService{storage: storage}
+}
+
+// FailureReason describes why a check failed
+func FailureReason(status models.WebsiteStatus) string {
+	switch {
+	case status.FailedAssertion != "":
+		return status.FailedAssertion
+	case status.Error != "":
+		return status.Error
+	case status.StatusCode != 0:
+		return fmt.Sprintf("HTTP %d", status.StatusCode)
+	default:
+		return ""
+	}
+}
+
+// Record updates a website's incidents with a processed check result.
+// alert is the alert ApplyCheckResult returned for it and isUp the
+// confirmed state after it: a down or flapping alert opens an incident,
+// an up alert (or stabilizing while up) resolves it, and other failures
+// are added to the open incident. It returns the incident the alert
+// belongs to, or nil.
+func (i *IncidentService) Record(website models.Website, status models.WebsiteStatus, alert string, isUp bool) (*models.Incident, error) {
+	now := time.Unix(status.CheckedAt, 0)
+	if status.CheckedAt == 0 {
+		now = time.Now()
+	}
+
+	switch {
+	case alert == models.AlertDown:
+		return i.open(website, status, now, FailureReason(status))
+	case alert == models.AlertUp:
+		return i.storage.ResolveIncident(website.ID, now.Unix(), "Website is back up")
+	case alert == models.AlertFlapping:
+		existing, err := i.storage.GetOpenIncident(website.ID)
+		if err != nil || existing == nil {
+			if err != nil {
+				return nil, err
+			}
+			return i.open(website, status, now, "Flapping")
+		}
+		event := models.IncidentEvent{Type: models.EventFlapping, At: now.Unix(), Message: "Started flapping"}
+		if err := i.storage.AddIncidentEvent(website.ID, event); err != nil {
+			return nil, err
+		}
+		existing.Timeline = append(existing.Timeline, event)
+		return existing, nil
+	case alert == models.AlertStabilized && isUp:
+		return i.storage.ResolveIncident(website.ID, now.Unix(), "Stabilized and back up")
+	case alert == models.AlertStabilized:
+		existing, err := i.storage.GetOpenIncident(website.ID)
+		if err != nil || existing == nil {
+			if err != nil {
+				return nil, err
+			}
+			return i.open(website, status, now, "Stabilized while down")
+		}
+		event := models.IncidentEvent{Type: models.EventStabilized, At: now.Unix(), Message: "Stabilized while down"}
+		if err := i.storage.AddIncidentEvent(website.ID, event); err != nil {
+			return nil, err
+		}
+		existing.Timeline = append(existing.Timeline, event)
+		return existing, nil
+	case !status.IsUp:
+		return nil, i.storage.RecordIncidentFailure(website.ID, status.StatusCode, FailureReason(status), now.Unix())
+	default:
+		return nil, nil
+	}
+}
+
+// open starts an incident for a website, opened for reason, unless one is
+// already open
+func (i *IncidentService) open(website models.Website, status models.WebsiteStatus, now time.Time, reason string) (*models.Incident, error) {
+	existing, err := i.storage.GetOpenIncident(website.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if !status.IsUp {
+			err = i.storage.RecordIncidentFailure(website.ID, status.StatusCode, FailureReason(status), now.Unix())
+		}
+		return existing, err
+	}
+
+	incident := models.Incident{
+		ID:                 primitive.NewObjectID().Hex(),
+		WebsiteID:          website.ID,
+		UserID:             website.UserID,
+		WebsiteName:        website.Name,
+		Status:             models.IncidentOpen,
+		StartedAt:          now.Unix(),
+		EscalationPolicyID: website.EscalationPolicyID,
+		Timeline: []models.IncidentEvent{
+			{Type: models.EventOpened, At: now.Unix(), Message: reason},
+		},
+	}
+	// A flapping website may open an incident on a successful check
+	if !status.IsUp {
+		incident.FailedChecks = 1
+		if status.StatusCode != 0 {
+			incident.StatusCodes = []int{status.StatusCode}
+		}
+		if failure := FailureReason(status); failure != "" {
+			incident.Errors = []string{failure}
+			incident.LastError = failure
+		}
+	}
+	if err := i.storage.SaveIncident(incident); err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// SummarizeIncidents aggregates the incidents that started at or after since
+func SummarizeIncidents(incidents []models.Incident, since time.Time) models.IncidentSummary {
+	summary := models.IncidentSummary{Since: since.Unix()}
+	for _, incident := range incidents {
+		if incident.StartedAt < summary.Since {
+			continue
+		}
+		summary.Total++
+		if incident.Status != models.IncidentResolved {
+			summary.Open++
+			continue
+		}
+		summary.Resolved++
+		summary.TotalDowntimeSeconds += incident.DurationSeconds
+	}
+	if summary.Resolved > 0 {
+		summary.MTTRSeconds = summary.TotalDowntimeSeconds / int64(summary.Resolved)
+	}
+	return summary
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		status models.WebsiteStatus
+		want   string
+	}{
+		{"assertion first", models.WebsiteStatus{FailedAssertion: "status is 200", Error: "boom", StatusCode: 500}, "status is 200"},
+		{"then the error", models.WebsiteStatus{Error: "connection refused", StatusCode: 500}, "connection refused"},
+		{"then the status code", models.WebsiteStatus{StatusCode: 503}, "HTTP 503"},
+		{"nothing", models.WebsiteStatus{}, ""},
+	}
+	for _, tt := range tests {
+		if got := FailureReason(tt.status); got != tt.want {
+			t.Errorf("%s: FailureReason() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeIncidents(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	resolved := func(startedAt, duration int64) models.Incident {
+		return models.Incident{Status: models.IncidentResolved, StartedAt: startedAt, DurationSeconds: duration}
+	}
+	open := func(startedAt int64) models.Incident {
+		return models.Incident{Status: models.IncidentOpen, StartedAt: startedAt}
+	}
+	tests := []struct {
+		name      string
+		incidents []models.Incident
+		want      models.IncidentSummary
+	}{
+		{
+			name: "no incidents",
+			want: models.IncidentSummary{Since: since.Unix()},
+		},
+		{
+			name:      "mean of the resolved",
+			incidents: []models.Incident{resolved(since.Unix(), 60), resolved(since.Unix()+100, 180), open(since.Unix() + 200)},
+			want:      models.IncidentSummary{Since: since.Unix(), Total: 3, Open: 1, Resolved: 2, MTTRSeconds: 120, TotalDowntimeSeconds: 240},
+		},
+		{
+			name:      "only open",
+			incidents: []models.Incident{open(since.Unix() + 10)},
+			want:      models.IncidentSummary{Since: since.Unix(), Total: 1, Open: 1},
+		},
+		{
+			name:      "older incidents skipped",
+			incidents: []models.Incident{resolved(since.Unix()-1, 3600), open(since.Unix() - 60), resolved(since.Unix()+5, 30)},
+			want:      models.IncidentSummary{Since: since.Unix(), Total: 1, Resolved: 1, MTTRSeconds: 30, TotalDowntimeSeconds: 30},
+		},
+	}
+	for _, tt := range tests {
+		if got := SummarizeIncidents(tt.incidents, since); got != tt.want {
+			t.Errorf("%s: SummarizeIncidents() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIncidentRecord(t *testing.T) {
+	website := models.Website{ID: "w1", UserID: "u1", Name: "Example", EscalationPolicyID: "p1"}
+	steps := []struct {
+		status    models.WebsiteStatus
+		alert     string
+		isUp      bool
+		wantEvent string // Last timeline event of the returned incident, "" for none
+	}{
+		{models.WebsiteStatus{CheckedAt: 1000, StatusCode: 503}, models.AlertDown, false, models.EventOpened},
+		{models.WebsiteStatus{CheckedAt: 1060, StatusCode: 503}, "", false, ""},
+		{models.WebsiteStatus{CheckedAt: 1120, Error: "connection refused"}, "", false, ""},
+		{models.WebsiteStatus{CheckedAt: 1180, IsUp: true}, models.AlertUp, true, models.EventResolved},
+		{models.WebsiteStatus{CheckedAt: 1240, IsUp: true}, "", true, ""},
+	}
+
+	incidents := NewIncidentService(newTestBoltStore(t, ""))
+	for i, step := range steps {
+		incident, err := incidents.Record(website, step.status, step.alert, step.isUp)
+		if err != nil {
+			t.Fatalf("step %d: Record() error = %v", i, err)
+		}
+		var gotEvent string
+		if incident != nil {
+			gotEvent = incident.Timeline[len(incident.Timeline)-1].Type
+		}
+		if gotEvent != step.wantEvent {
+			t.Errorf("step %d: Record() event = %q, want %q", i, gotEvent, step.wantEvent)
+		}
+	}
+
+	recorded, err := incidents.storage.GetIncidentsByWebsite("w1", 0)
+	if err != nil || len(recorded) != 1 {
+		t.Fatalf("GetIncidentsByWebsite() = %+v, %v, want one incident", recorded, err)
+	}
+	got := recorded[0]
+	if got.Status != models.IncidentResolved || got.DurationSeconds != 180 || got.FailedChecks != 3 ||
+		got.LastError != "connection refused" || got.EscalationPolicyID != "p1" || got.UserID != "u1" {
+		t.Errorf("incident = %+v, want resolved after 180s with 3 failed checks", got)
+	}
+}
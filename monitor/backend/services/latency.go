@@ -0,0 +1,126 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+const (
+	// defaultDegradedChecks is how many slow checks in a row degrade a website
+	defaultDegradedChecks = 3
+	// defaultDegradedWindow is how many recent response times the p95 covers
+	defaultDegradedWindow = 20
+	// maxDegradedWindow caps the response times kept in a monitor's state
+	maxDegradedWindow = 100
+)
+
+// DegradedChecks returns how many slow checks in a row mark a website degraded
+func DegradedChecks(website models.Website) int {
+	if website.DegradedChecks <= 0 {
+		return defaultDegradedChecks
+	}
+	return website.DegradedChecks
+}
+
+// DegradedWindow returns how many recent response times the p95 is taken over
+func DegradedWindow(website models.Website) int {
+	switch {
+	case website.DegradedWindow <= 0:
+		return defaultDegradedWindow
+	case website.DegradedWindow > maxDegradedWindow:
+		return maxDegradedWindow
+	}
+	return website.DegradedWindow
+}
+
+// tracksLatency reports whether a website's response times are watched:
+// it has a latency threshold and is requested by us, unlike push monitors
+func tracksLatency(website models.Website) bool {
+	return website.DegradedThresholdMs > 0 && website.Type != models.MonitorPush
+}
+
+// IsSlow reports whether a successful check was slower than the website's
+// latency threshold
+func IsSlow(website models.Website, status models.WebsiteStatus) bool {
+	return tracksLatency(website) && status.IsUp &&
+		status.ResponseTime > int64(website.DegradedThresholdMs)
+}
+
+// Percentile returns the nearest-rank pth percentile of values, or 0 if
+// there are none
+func Percentile(values []int64, p int) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// WindowP95 returns the p95 of a monitor's recent response times, or 0
+// until the website's window is full
+func WindowP95(state *models.MonitorState, website models.Website) int64 {
+	window := DegradedWindow(website)
+	if state == nil || len(state.RecentResponseTimes) < window {
+		return 0
+	}
+	return Percentile(state.RecentResponseTimes[len(state.RecentResponseTimes)-window:], 95)
+}
+
+// ApplyLatency records a check's response time on a monitor's state and
+// returns AlertDegraded or AlertRestored when the website becomes slow or
+// fast again, or "" otherwise. Failed checks are left to ApplyCheckResult
+// and don't count either way. A website is degraded after DegradedChecks
+// slow checks in a row, or once the p95 over its window exceeds the
+// threshold, and restored when neither holds. Nothing is sent while the
+// website is down or flapping. Push monitors have no response time.
+func ApplyLatency(state *models.MonitorState, website models.Website, status models.WebsiteStatus, now time.Time) string {
+	if !tracksLatency(website) {
+		state.ConsecutiveSlow = 0
+		state.RecentResponseTimes = nil
+		if state.Degraded {
+			state.Degraded = false
+			state.DegradedSince = 0
+			return models.AlertRestored
+		}
+		return ""
+	}
+	if !status.IsUp {
+		return ""
+	}
+
+	state.RecentResponseTimes = append(state.RecentResponseTimes, status.ResponseTime)
+	if window := DegradedWindow(website); len(state.RecentResponseTimes) > window {
+		state.RecentResponseTimes = state.RecentResponseTimes[len(state.RecentResponseTimes)-window:]
+	}
+	if IsSlow(website, status) {
+		state.ConsecutiveSlow++
+	} else {
+		state.ConsecutiveSlow = 0
+	}
+
+	threshold := int64(website.DegradedThresholdMs)
+	p95 := WindowP95(state, website)
+	slow := state.ConsecutiveSlow >= DegradedChecks(website) || p95 > threshold
+	if !state.IsUp || state.Flapping {
+		return ""
+	}
+
+	switch {
+	case !state.Degraded && slow:
+		state.Degraded = true
+		state.DegradedSince = now.Unix()
+		return models.AlertDegraded
+	case state.Degraded && !slow && state.ConsecutiveSlow == 0:
+		state.Degraded = false
+		state.DegradedSince = 0
+		return models.AlertRestored
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestApplyLatency(t *testing.T) {
+	const down = -1 // A failed check instead of a response time
+	tests := []struct {
+		name      string
+		website   models.Website
+		times     []int64
+		want      []string // Alert returned for each check
+		wantState bool     // Degraded afterwards
+	}{
+		{
+			name:    "no threshold never degrades",
+			website: models.Website{},
+			times:   []int64{5000, 5000, 5000},
+			want:    []string{"", "", ""},
+		},
+		{
+			name:      "slow checks in a row degrade",
+			website:   models.Website{DegradedThresholdMs: 500},
+			times:     []int64{600, 700, 800, 900},
+			want:      []string{"", "", models.AlertDegraded, ""},
+			wantState: true,
+		},
+		{
+			name:      "a fast check resets the run",
+			website:   models.Website{DegradedThresholdMs: 500},
+			times:     []int64{600, 700, 100, 600, 700},
+			want:      []string{"", "", "", "", ""},
+			wantState: false,
+		},
+		{
+			name:      "failed checks don't count",
+			website:   models.Website{DegradedThresholdMs: 500},
+			times:     []int64{600, down, 700, down, 800},
+			want:      []string{"", "", "", "", models.AlertDegraded},
+			wantState: true,
+		},
+		{
+			name:    "restored once fast again",
+			website: models.Website{DegradedThresholdMs: 500, DegradedChecks: 2},
+			times:   []int64{600, 700, 100},
+			want:    []string{"", models.AlertDegraded, models.AlertRestored},
+		},
+		{
+			name:      "p95 over the window degrades",
+			website:   models.Website{DegradedThresholdMs: 500, DegradedChecks: 10, DegradedWindow: 4},
+			times:     []int64{100, 900, 100, 900},
+			want:      []string{"", "", "", models.AlertDegraded},
+			wantState: true,
+		},
+		{
+			name:    "push monitors have no response time",
+			website: models.Website{Type: models.MonitorPush, DegradedThresholdMs: 500, DegradedChecks: 1},
+			times:   []int64{5000, 5000},
+			want:    []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &models.MonitorState{IsUp: true}
+			now := time.Unix(1700000000, 0)
+			var got []string
+			for _, ms := range tt.times {
+				status := models.WebsiteStatus{IsUp: ms != down, ResponseTime: ms}
+				got = append(got, ApplyLatency(state, tt.website, status, now))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alerts = %q, want %q", got, tt.want)
+			}
+			if state.Degraded != tt.wantState {
+				t.Errorf("Degraded = %v, want %v", state.Degraded, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestApplyLatencyWhileDown(t *testing.T) {
+	website := models.Website{DegradedThresholdMs: 500, DegradedChecks: 1}
+	slow := models.WebsiteStatus{IsUp: true, ResponseTime: 900}
+
+	for _, state := range []*models.MonitorState{{IsUp: false}, {IsUp: true, Flapping: true}} {
+		if alert := ApplyLatency(state, website, slow, time.Now()); alert != "" || state.Degraded {
+			t.Errorf("ApplyLatency() with state %+v = %q, want no alert", state, alert)
+		}
+	}
+}
+
+func TestApplyLatencyThresholdRemoved(t *testing.T) {
+	state := &models.MonitorState{IsUp: true, Degraded: true, DegradedSince: 1700000000, RecentResponseTimes: []int64{900}}
+	alert := ApplyLatency(state, models.Website{}, models.WebsiteStatus{IsUp: true, ResponseTime: 900}, time.Now())
+	if alert != models.AlertRestored || state.Degraded || state.RecentResponseTimes != nil {
+		t.Errorf("ApplyLatency() = %q with state %+v, want restored and cleared", alert, state)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []int64
+		p      int
+		want   int64
+	}{
+		{nil, 95, 0},
+		{[]int64{42}, 95, 42},
+		{[]int64{5, 1, 4, 2, 3}, 50, 3},
+		{[]int64{5, 1, 4, 2, 3}, 95, 5},
+		{[]int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100, 110, 120, 130, 140, 150, 160, 170, 180, 190, 200}, 95, 190},
+	}
+	for _, tt := range tests {
+		if got := Percentile(tt.values, tt.p); got != tt.want {
+			t.Errorf("Percentile(%v, %d) = %d, want %d", tt.values, tt.p, got, tt.want)
+		}
+	}
+}
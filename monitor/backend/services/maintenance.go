@@ -0,0 +1,123 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"github.com/prateeks007/PulseWatch/monitor/backend/utils"
+)
+
+// maintenanceCacheTTL is how long a user's windows are cached between checks
+const maintenanceCacheTTL = 30 * time.Second
+
+// MaintenanceService decides whether a website is in a maintenance window.
+// Windows are cached per user since every check asks.
+type MaintenanceService struct {
+	storage Store
+
+	mu    sync.Mutex
+	cache map[string]cachedWindows
+}
+
+// cachedWindows is a user's windows as of fetchedAt
+type cachedWindows struct {
+	windows   []models.MaintenanceWindow
+	fetchedAt time.Time
+}
+
+// NewMaintenanceService creates a maintenance service backed by storage
+func NewMaintenanceService(storage Store) *MaintenanceService {
+	return &MaintenanceService{storage: storage, cache: make(map[string]cachedWindows)}
+}
+
+// Active returns the maintenance window a website is in at now, or nil
+func (m *MaintenanceService) Active(website models.Website, now time.Time) (*models.MaintenanceWindow, error) {
+	windows, err := m.windows(website.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range windows {
+		if MaintenanceCovers(windows[i], website) && MaintenanceActive(windows[i], now) {
+			return &windows[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Invalidate drops the cached windows of a user after they changed
+func (m *MaintenanceService) Invalidate(userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.cache, userID)
+}
+
+// windows returns a user's windows, from the cache if it is fresh
+func (m *MaintenanceService) windows(userID string) ([]models.MaintenanceWindow, error) {
+	m.mu.Lock()
+	cached, ok := m.cache[userID]
+	m.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < maintenanceCacheTTL {
+		return cached.windows, nil
+	}
+
+	windows, err := m.storage.GetMaintenanceWindowsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	m.cache[userID] = cachedWindows{windows: windows, fetchedAt: time.Now()}
+	m.mu.Unlock()
+	return windows, nil
+}
+
+// MaintenanceCovers reports whether a window applies to a website, by ID or tag
+func MaintenanceCovers(window models.MaintenanceWindow, website models.Website) bool {
+	for _, id := range window.WebsiteIDs {
+		if id == website.ID {
+			return true
+		}
+	}
+	for _, tag := range window.Tags {
+		for _, websiteTag := range website.Tags {
+			if tag == websiteTag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MaintenanceActive reports whether a window is in progress at now
+func MaintenanceActive(window models.MaintenanceWindow, now time.Time) bool {
+	if window.Schedule == "" {
+		return now.Unix() >= window.StartsAt && now.Unix() < window.EndsAt
+	}
+
+	schedule, err := utils.ParseMaintenanceSchedule(window.Schedule, window.Timezone)
+	if err != nil {
+		return false
+	}
+	// An occurrence is in progress if one started within the last DurationMinutes
+	duration := time.Duration(window.DurationMinutes) * time.Minute
+	return !schedule.Next(now.Add(-duration)).After(now)
+}
+
+// NextMaintenance returns when a window next starts and ends after now,
+// or zero times if it never will
+func NextMaintenance(window models.MaintenanceWindow, now time.Time) (start, end time.Time) {
+	if window.Schedule == "" {
+		if now.Unix() >= window.EndsAt {
+			return time.Time{}, time.Time{}
+		}
+		return time.Unix(window.StartsAt, 0), time.Unix(window.EndsAt, 0)
+	}
+
+	schedule, err := utils.ParseMaintenanceSchedule(window.Schedule, window.Timezone)
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	duration := time.Duration(window.DurationMinutes) * time.Minute
+	start = schedule.Next(now.Add(-duration))
+	return start, start.Add(duration)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestMaintenanceActive(t *testing.T) {
+	oneOff := models.MaintenanceWindow{StartsAt: 1700000000, EndsAt: 1700003600}
+	// Tuesdays 22:00-23:00 UTC
+	weekly := models.MaintenanceWindow{Schedule: "FREQ=WEEKLY;BYDAY=TU;BYHOUR=22", DurationMinutes: 60}
+	tuesday := func(hour, minute int) time.Time { return time.Date(2024, 3, 5, hour, minute, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		window models.MaintenanceWindow
+		now    time.Time
+		want   bool
+	}{
+		{"before a one-off window", oneOff, time.Unix(1699999999, 0), false},
+		{"at the start of a one-off window", oneOff, time.Unix(1700000000, 0), true},
+		{"at the end of a one-off window", oneOff, time.Unix(1700003600, 0), false},
+		{"before an occurrence", weekly, tuesday(21, 59), false},
+		{"at the start of an occurrence", weekly, tuesday(22, 0), true},
+		{"during an occurrence", weekly, tuesday(22, 59), true},
+		{"after an occurrence", weekly, tuesday(23, 0), false},
+		{"on another day", weekly, tuesday(22, 30).AddDate(0, 0, 1), false},
+		{"a week later", weekly, tuesday(22, 30).AddDate(0, 0, 7), true},
+		{"cron schedule", models.MaintenanceWindow{Schedule: "0 22 * * 2", DurationMinutes: 30}, tuesday(22, 15), true},
+		{"invalid schedule", models.MaintenanceWindow{Schedule: "FREQ=HOURLY", DurationMinutes: 60}, tuesday(22, 15), false},
+	}
+	for _, tt := range tests {
+		if got := MaintenanceActive(tt.window, tt.now); got != tt.want {
+			t.Errorf("%s: MaintenanceActive() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextMaintenance(t *testing.T) {
+	weekly := models.MaintenanceWindow{Schedule: "0 22 * * 2", DurationMinutes: 60}
+	tuesday := time.Date(2024, 3, 5, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		window    models.MaintenanceWindow
+		now       time.Time
+		wantStart time.Time
+	}{
+		{"upcoming one-off", models.MaintenanceWindow{StartsAt: 1700000000, EndsAt: 1700003600}, time.Unix(1690000000, 0), time.Unix(1700000000, 0)},
+		{"one-off in progress", models.MaintenanceWindow{StartsAt: 1700000000, EndsAt: 1700003600}, time.Unix(1700000100, 0), time.Unix(1700000000, 0)},
+		{"past one-off", models.MaintenanceWindow{StartsAt: 1700000000, EndsAt: 1700003600}, time.Unix(1700003600, 0), time.Time{}},
+		{"next occurrence", weekly, tuesday.Add(-24 * time.Hour), tuesday},
+		{"occurrence in progress", weekly, tuesday.Add(30 * time.Minute), tuesday},
+		{"after an occurrence", weekly, tuesday.Add(time.Hour), tuesday.AddDate(0, 0, 7)},
+		{"invalid schedule", models.MaintenanceWindow{Schedule: "bogus"}, tuesday, time.Time{}},
+	}
+	for _, tt := range tests {
+		start, end := NextMaintenance(tt.window, tt.now)
+		if !start.Equal(tt.wantStart) {
+			t.Errorf("%s: NextMaintenance() starts %s, want %s", tt.name, start, tt.wantStart)
+		}
+		if tt.wantStart.IsZero() != end.IsZero() || (tt.window.Schedule != "" && !end.IsZero() && end.Sub(start) != time.Hour) {
+			t.Errorf("%s: NextMaintenance() ends %s", tt.name, end)
+		}
+	}
+}
+
+func TestMaintenanceCovers(t *testing.T) {
+	website := models.Website{ID: "w1", Tags: []string{"prod", "api"}}
+	tests := []struct {
+		window models.MaintenanceWindow
+		want   bool
+	}{
+		{models.MaintenanceWindow{WebsiteIDs: []string{"w2", "w1"}}, true},
+		{models.MaintenanceWindow{Tags: []string{"api"}}, true},
+		{models.MaintenanceWindow{WebsiteIDs: []string{"w2"}, Tags: []string{"staging"}}, false},
+		{models.MaintenanceWindow{}, false},
+	}
+	for _, tt := range tests {
+		if got := MaintenanceCovers(tt.window, website); got != tt.want {
+			t.Errorf("MaintenanceCovers(%+v) = %v, want %v", tt.window, got, tt.want)
+		}
+	}
+}
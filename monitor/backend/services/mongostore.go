@@ -0,0 +1,924 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// MongoStore is the Store backed by MongoDB
+type MongoStore struct {
+	client          *mongo.Client
+	websitesColl    *mongo.Collection
+	statusesColl    *mongo.Collection
+	sslColl         *mongo.Collection
+	usersColl       *mongo.Collection
+	statesColl      *mongo.Collection
+	incidentsColl   *mongo.Collection
+	maintenanceColl *mongo.Collection
+	sslHistoryColl  *mongo.Collection
+	domainsColl     *mongo.Collection
+	databaseName    string
+	mongoURI        string
+}
+
+// NewMongoStore connects to the MongoDB database dbName at uri
+func NewMongoStore(uri, dbName string) (*MongoStore, error) {
+	if uri == "" {
+		return nil, fmt.Errorf("MONGO_URI environment variable not set")
+	}
+	s := &MongoStore{mongoURI: uri, databaseName: dbName}
+	if err := s.ConnectMongoDB(uri, dbName); err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+	log.Printf("✅ Storage service initialized successfully")
+	return s, nil
+}
+
+func (s *MongoStore) ConnectMongoDB(uri, dbName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	clientOptions := options.Client().ApplyURI(uri)
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return err
+	}
+	if err = client.Ping(ctx, readpref.Primary()); err != nil {
+		return err
+	}
+	s.client = client
+	db := client.Database(dbName)
+	s.websitesColl = db.Collection("websites")
+	s.statusesColl = db.Collection("statuses")
+	s.sslColl = db.Collection("ssl")
+	s.usersColl = db.Collection("users")
+	s.statesColl = db.Collection("monitor_states")
+	s.incidentsColl = db.Collection("incidents")
+	s.maintenanceColl = db.Collection("maintenance_windows")
+	s.sslHistoryColl = db.Collection("ssl_history")
+	s.domainsColl = db.Collection("domains")
+
+	log.Println("Connected to Mongo!")
+	return nil
+}
+
+// Close disconnects from MongoDB
+func (s *MongoStore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.client.Disconnect(ctx)
+}
+
+func (s *MongoStore) GetWebsites() ([]models.Website, error) {
+	var sites []models.Website
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := s.websitesColl.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find websites: %w", err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("⚠️ Failed to close cursor: %v", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, &sites); err != nil {
+		return nil, fmt.Errorf("failed to decode websites: %w", err)
+	}
+	return sites, nil
+}
+
+// GetWebsitesByUser returns websites filtered by user_id
+func (s *MongoStore) GetWebsitesByUser(userID string) ([]models.Website, error) {
+	var sites []models.Website
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := s.websitesColl.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find websites for user %s: %w", userID, err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("⚠️ Failed to close cursor: %v", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, &sites); err != nil {
+		return nil, fmt.Errorf("failed to decode websites for user %s: %w", userID, err)
+	}
+	return sites, nil
+}
+
+func (s *MongoStore) SaveWebsite(website models.Website) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := s.websitesColl.UpdateOne(
+		ctx,
+		bson.M{"_id": website.ID},
+		bson.M{"$set": website},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save website %s: %w", website.Name, err)
+	}
+	return nil
+}
+
+func (s *MongoStore) SaveStatus(status models.WebsiteStatus) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	doc := bson.M{
+		"website_id":       status.WebsiteID,
+		"is_up":            status.IsUp,
+		"status_code":      status.StatusCode,
+		"response_time_ms": status.ResponseTime,
+		"checked_at":       status.CheckedAt,
+		"checked_at_date":  time.Unix(status.CheckedAt, 0),
+		"attempts":         status.Attempts,
+	}
+	if status.Error != "" {
+		doc["error"] = status.Error
+	}
+	if status.FailedAssertion != "" {
+		doc["failed_assertion"] = status.FailedAssertion
+	}
+	if status.Timings != nil {
+		doc["timings"] = status.Timings
+	}
+	if status.Maintenance {
+		doc["maintenance"] = true
+	}
+	if status.Degraded {
+		doc["degraded"] = true
+	}
+	if status.JobDurationMs != 0 {
+		doc["job_duration_ms"] = status.JobDurationMs
+	}
+	_, err := s.statusesColl.InsertOne(ctx, doc)
+	if err != nil {
+		return fmt.Errorf("failed to save status for website %s: %w", status.WebsiteID, err)
+	}
+	return nil
+}
+
+func (s *MongoStore) GetWebsiteStatuses(websiteID string) ([]models.WebsiteStatus, error) {
+	var statuses []models.WebsiteStatus
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	cursor, err := s.statusesColl.Find(
+		ctx,
+		bson.M{"website_id": websiteID},
+		options.Find().SetSort(bson.D{{Key: "checked_at", Value: -1}}).SetLimit(500),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find statuses for website %s: %w", websiteID, err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("⚠️ Failed to close cursor: %v", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, &statuses); err != nil {
+		return nil, fmt.Errorf("failed to decode statuses for website %s: %w", websiteID, err)
+	}
+	return statuses, nil
+}
+
+// --- SSL ---
+
+func (s *MongoStore) SaveSSL(info models.SSLInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := s.sslColl.UpdateOne(
+		ctx,
+		bson.M{"website_id": info.WebsiteID},
+		bson.M{"$set": info},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		log.Printf("SaveSSL UpdateOne error: %v", err)
+	}
+	return err
+}
+
+func (s *MongoStore) GetSSL(websiteID string) *models.SSLInfo {
+	var out models.SSLInfo
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.sslColl.FindOne(ctx, bson.M{"website_id": websiteID}).Decode(&out)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("GetSSL error: %v", err)
+		}
+		return nil
+	}
+	return &out
+}
+
+// RecordCertificate adds a certificate to its website's history, or
+// refreshes its last_seen if it is already there. It reports whether the
+// certificate is new.
+func (s *MongoStore) RecordCertificate(record models.CertificateRecord) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Everything but last_seen is kept from when it was first seen
+	doc, err := bson.Marshal(record)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode certificate record: %w", err)
+	}
+	var onInsert bson.M
+	if err := bson.Unmarshal(doc, &onInsert); err != nil {
+		return false, fmt.Errorf("failed to encode certificate record: %w", err)
+	}
+	delete(onInsert, "_id") // Set from the filter
+	delete(onInsert, "last_seen")
+
+	result, err := s.sslHistoryColl.UpdateOne(
+		ctx,
+		bson.M{"_id": record.ID},
+		bson.M{"$setOnInsert": onInsert, "$set": bson.M{"last_seen": record.LastSeen}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record certificate %s: %w", record.Fingerprint, err)
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// GetCertificateHistory returns the certificates a website has served, newest first
+func (s *MongoStore) GetCertificateHistory(websiteID string) ([]models.CertificateRecord, error) {
+	records := []models.CertificateRecord{}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := s.sslHistoryColl.Find(ctx, bson.M{"website_id": websiteID}, options.Find().SetSort(bson.D{{Key: "first_seen", Value: -1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find certificate history for website %s: %w", websiteID, err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("⚠️ Failed to close cursor: %v", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode certificate history for website %s: %w", websiteID, err)
+	}
+	return records, nil
+}
+
+// SaveDomain stores the registration of an apex domain
+func (s *MongoStore) SaveDomain(info models.DomainInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.domainsColl.ReplaceOne(ctx, bson.M{"_id": info.Domain}, info, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save domain %s: %w", info.Domain, err)
+	}
+	return nil
+}
+
+// GetDomain returns the stored registration of an apex domain, or nil if
+// it hasn't been looked up
+func (s *MongoStore) GetDomain(domain string) (*models.DomainInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var info models.DomainInfo
+	err := s.domainsColl.FindOne(ctx, bson.M{"_id": domain}).Decode(&info)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain %s: %w", domain, err)
+	}
+	return &info, nil
+}
+
+func (s *MongoStore) DeleteWebsite(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.websitesColl.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		log.Printf("DeleteWebsite websites delete error: %v", err)
+	}
+	if _, err := s.statusesColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsite statuses delete error: %v", err)
+	}
+	if _, err := s.sslColl.DeleteOne(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsite ssl delete error: %v", err)
+	}
+	if _, err := s.statesColl.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		log.Printf("DeleteWebsite monitor state delete error: %v", err)
+	}
+	if _, err := s.incidentsColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsite incidents delete error: %v", err)
+	}
+	if _, err := s.sslHistoryColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsite ssl history delete error: %v", err)
+	}
+	return nil
+}
+
+// DeleteWebsiteByUser deletes a website only if it belongs to the user
+func (s *MongoStore) DeleteWebsiteByUser(id, userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Delete only if the website belongs to the user
+	result, err := s.websitesColl.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		log.Printf("DeleteWebsiteByUser websites delete error: %v", err)
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("website not found or access denied")
+	}
+
+	// Clean up related data
+	if _, err := s.statusesColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsiteByUser statuses delete error: %v", err)
+	}
+	if _, err := s.sslColl.DeleteOne(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsiteByUser ssl delete error: %v", err)
+	}
+	if _, err := s.statesColl.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		log.Printf("DeleteWebsiteByUser monitor state delete error: %v", err)
+	}
+	if _, err := s.incidentsColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsiteByUser incidents delete error: %v", err)
+	}
+	if _, err := s.sslHistoryColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
+		log.Printf("DeleteWebsiteByUser ssl history delete error: %v", err)
+	}
+	return nil
+}
+
+// GetWebsiteByUser returns a website only if it belongs to the user
+func (s *MongoStore) GetWebsiteByUser(id, userID string) (*models.Website, error) {
+	var website models.Website
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.websitesColl.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&website)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("website not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to find website: %w", err)
+	}
+	return &website, nil
+}
+
+// GetWebsiteByPushToken returns the push monitor with the given ping token
+func (s *MongoStore) GetWebsiteByPushToken(token string) (*models.Website, error) {
+	var website models.Website
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.websitesColl.FindOne(ctx, bson.M{"push_token": token, "type": models.MonitorPush}).Decode(&website)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("push monitor not found")
+		}
+		return nil, fmt.Errorf("failed to find push monitor: %w", err)
+	}
+	return &website, nil
+}
+
+// DeleteStatusesBefore deletes the statuses checked before the given Unix
+// timestamp and returns how many were deleted
+func (s *MongoStore) DeleteStatusesBefore(before int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := s.statusesColl.DeleteMany(ctx, bson.M{"checked_at": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old statuses: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteOrphanedStatuses deletes the statuses of websites that no longer
+// exist and returns how many were deleted
+func (s *MongoStore) DeleteOrphanedStatuses() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	validIDs, err := s.websitesColl.Distinct(ctx, "_id", bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get websites: %w", err)
+	}
+	result, err := s.statusesColl.DeleteMany(ctx, bson.M{"website_id": bson.M{"$nin": validIDs}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphaned statuses: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// --- User Management ---
+
+// GetUser returns user settings by user ID
+func (s *MongoStore) GetUser(userID string) (*models.User, error) {
+	var user models.User
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.usersColl.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // User not found, return nil
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	return &user, nil
+}
+
+// SaveUser saves or updates user settings
+func (s *MongoStore) SaveUser(user models.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user.UpdatedAt = time.Now().Unix()
+	if user.CreatedAt == 0 {
+		user.CreatedAt = user.UpdatedAt
+	}
+
+	_, err := s.usersColl.UpdateOne(
+		ctx,
+		bson.M{"_id": user.ID},
+		bson.M{"$set": user},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save user %s: %w", user.ID, err)
+	}
+	return nil
+}
+
+// GetUserDiscordWebhook returns the Discord webhook URL for a user
+func (s *MongoStore) GetUserDiscordWebhook(userID string) (string, error) {
+	user, err := s.GetUser(userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", nil // User not found, no webhook
+	}
+	return user.DiscordWebhookURL, nil
+}
+
+// --- Monitor State ---
+
+// GetMonitorState returns the alerting state of a website, or nil if it has never been checked
+func (s *MongoStore) GetMonitorState(websiteID string) (*models.MonitorState, error) {
+	var state models.MonitorState
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.statesColl.FindOne(ctx, bson.M{"_id": websiteID}).Decode(&state)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find monitor state for website %s: %w", websiteID, err)
+	}
+	return &state, nil
+}
+
+// UpdateMonitorState applies update to the stored state of a website and saves it.
+// Writes are guarded by the state's version, so concurrent updates from
+// overlapping checks or other instances are retried against the latest state;
+// update may therefore run more than once and must only depend on its argument.
+func (s *MongoStore) UpdateMonitorState(websiteID string, update func(*models.MonitorState)) (*models.MonitorState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for attempt := 0; attempt < 5; attempt++ {
+		var state models.MonitorState
+		err := s.statesColl.FindOne(ctx, bson.M{"_id": websiteID}).Decode(&state)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("failed to find monitor state for website %s: %w", websiteID, err)
+		}
+		exists := err == nil
+
+		version := state.Version
+		update(&state)
+		state.WebsiteID = websiteID
+		state.Version = version + 1
+
+		if !exists {
+			if _, err := s.statesColl.InsertOne(ctx, state); err != nil {
+				if mongo.IsDuplicateKeyError(err) {
+					continue // Another check created it first
+				}
+				return nil, fmt.Errorf("failed to save monitor state for website %s: %w", websiteID, err)
+			}
+			return &state, nil
+		}
+
+		result, err := s.statesColl.ReplaceOne(ctx, bson.M{"_id": websiteID, "version": version}, state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save monitor state for website %s: %w", websiteID, err)
+		}
+		if result.MatchedCount == 1 {
+			return &state, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to save monitor state for website %s: too many concurrent updates", websiteID)
+}
+
+// --- Incidents ---
+
+// SaveIncident inserts a new incident
+func (s *MongoStore) SaveIncident(incident models.Incident) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.incidentsColl.InsertOne(ctx, incident); err != nil {
+		return fmt.Errorf("failed to save incident for website %s: %w", incident.WebsiteID, err)
+	}
+	return nil
+}
+
+// GetOpenIncident returns the open incident of a website, or nil if it has none
+func (s *MongoStore) GetOpenIncident(websiteID string) (*models.Incident, error) {
+	var incident models.Incident
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.incidentsColl.FindOne(
+		ctx,
+		bson.M{"website_id": websiteID, "status": models.IncidentOpen},
+		options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}}),
+	).Decode(&incident)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find open incident for website %s: %w", websiteID, err)
+	}
+	return &incident, nil
+}
+
+// RecordIncidentFailure adds a failed check to a website's open incident.
+// A reason not seen before in the incident is also added to its timeline.
+func (s *MongoStore) RecordIncidentFailure(websiteID string, statusCode int, reason string, at int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$inc": bson.M{"failed_checks": 1}}
+	if statusCode != 0 {
+		update["$addToSet"] = bson.M{"status_codes": statusCode}
+	}
+	if reason != "" {
+		update["$set"] = bson.M{"last_error": reason}
+	}
+	filter := bson.M{"website_id": websiteID, "status": models.IncidentOpen}
+	if _, err := s.incidentsColl.UpdateMany(ctx, filter, update); err != nil {
+		return fmt.Errorf("failed to update incident for website %s: %w", websiteID, err)
+	}
+	if reason == "" {
+		return nil
+	}
+
+	// Only a new reason is recorded, and only while under the cap
+	filter["errors"] = bson.M{"$ne": reason}
+	filter[fmt.Sprintf("errors.%d", models.MaxIncidentErrors-1)] = bson.M{"$exists": false}
+	_, err := s.incidentsColl.UpdateMany(ctx, filter, bson.M{"$push": bson.M{
+		"errors":   reason,
+		"timeline": models.IncidentEvent{Type: models.EventFailure, At: at, Message: reason},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to update incident for website %s: %w", websiteID, err)
+	}
+	return nil
+}
+
+// AddIncidentEvent appends an event to the timeline of a website's open incident
+func (s *MongoStore) AddIncidentEvent(websiteID string, event models.IncidentEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.incidentsColl.UpdateMany(
+		ctx,
+		bson.M{"website_id": websiteID, "status": models.IncidentOpen},
+		bson.M{"$push": bson.M{"timeline": event}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update incident for website %s: %w", websiteID, err)
+	}
+	return nil
+}
+
+// ResolveIncident resolves the open incident of a website and returns it,
+// or nil if the website had none
+func (s *MongoStore) ResolveIncident(websiteID string, at int64, message string) (*models.Incident, error) {
+	incident, err := s.GetOpenIncident(websiteID)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	event := models.IncidentEvent{Type: models.EventResolved, At: at, Message: message}
+	duration := at - incident.StartedAt
+	result, err := s.incidentsColl.UpdateOne(
+		ctx,
+		bson.M{"_id": incident.ID, "status": models.IncidentOpen},
+		bson.M{
+			"$set": bson.M{
+				"status":           models.IncidentResolved,
+				"resolved_at":      at,
+				"duration_seconds": duration,
+			},
+			"$push": bson.M{"timeline": event},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve incident %s: %w", incident.ID, err)
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil // Resolved concurrently
+	}
+
+	incident.Status = models.IncidentResolved
+	incident.ResolvedAt = at
+	incident.DurationSeconds = duration
+	incident.Timeline = append(incident.Timeline, event)
+	return incident, nil
+}
+
+// GetIncidentByUser returns an incident only if it belongs to the user
+func (s *MongoStore) GetIncidentByUser(id, userID string) (*models.Incident, error) {
+	var incident models.Incident
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.incidentsColl.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&incident)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("incident not found or access denied")
+		}
+		return nil, fmt.Errorf("failed to find incident: %w", err)
+	}
+	return &incident, nil
+}
+
+// GetIncidentsByUser returns a user's incidents, newest first, optionally
+// only those with the given status
+func (s *MongoStore) GetIncidentsByUser(userID, status string, limit int64) ([]models.Incident, error) {
+	filter := bson.M{"user_id": userID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return s.findIncidents(filter, limit)
+}
+
+// GetIncidentsByWebsite returns a website's incidents, newest first
+func (s *MongoStore) GetIncidentsByWebsite(websiteID string, limit int64) ([]models.Incident, error) {
+	return s.findIncidents(bson.M{"website_id": websiteID}, limit)
+}
+
+// GetIncidentsSince returns a user's incidents that started at or after
+// since, optionally only those of one website
+func (s *MongoStore) GetIncidentsSince(userID, websiteID string, since int64) ([]models.Incident, error) {
+	filter := bson.M{"user_id": userID, "started_at": bson.M{"$gte": since}}
+	if websiteID != "" {
+		filter["website_id"] = websiteID
+	}
+	return s.findIncidents(filter, 0)
+}
+
+// findIncidents returns the incidents matching filter, newest first
+func (s *MongoStore) findIncidents(filter bson.M, limit int64) ([]models.Incident, error) {
+	incidents := []models.Incident{}
+	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cursor, err := s.incidentsColl.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incidents: %w", err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("⚠️ Failed to close cursor: %v", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, &incidents); err != nil {
+		return nil, fmt.Errorf("failed to decode incidents: %w", err)
+	}
+	return incidents, nil
+}
+
+// AcknowledgeIncident marks an open incident as acknowledged by by. It
+// returns the incident, and whether this call acknowledged it (false if
+// it already was).
+func (s *MongoStore) AcknowledgeIncident(id, by string, at int64) (*models.Incident, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.incidentsColl.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "status": models.IncidentOpen, "acknowledged_at": bson.M{"$in": bson.A{nil, 0}}},
+		bson.M{
+			"$set":  bson.M{"acknowledged_at": at, "acknowledged_by": by},
+			"$push": bson.M{"timeline": models.IncidentEvent{Type: models.EventAcknowledged, At: at, Message: "Acknowledged by " + by}},
+		},
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acknowledge incident %s: %w", id, err)
+	}
+
+	incident, err := s.getIncident(id)
+	if err != nil {
+		return nil, false, err
+	}
+	return incident, result.ModifiedCount == 1, nil
+}
+
+// AddIncidentNote appends a note to an incident
+func (s *MongoStore) AddIncidentNote(id string, note models.IncidentNote) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.incidentsColl.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$push": bson.M{
+		"notes":    note,
+		"timeline": models.IncidentEvent{Type: models.EventNote, At: note.At, Message: note.Text},
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to add note to incident %s: %w", id, err)
+	}
+	return nil
+}
+
+// SetIncidentRootCause sets the root cause of an incident
+func (s *MongoStore) SetIncidentRootCause(id, rootCause string, at int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.incidentsColl.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":  bson.M{"root_cause": rootCause},
+		"$push": bson.M{"timeline": models.IncidentEvent{Type: models.EventRootCause, At: at, Message: rootCause}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set root cause of incident %s: %w", id, err)
+	}
+	return nil
+}
+
+// getIncident returns an incident by ID
+func (s *MongoStore) getIncident(id string) (*models.Incident, error) {
+	var incident models.Incident
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.incidentsColl.FindOne(ctx, bson.M{"_id": id}).Decode(&incident)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("incident not found")
+		}
+		return nil, fmt.Errorf("failed to find incident: %w", err)
+	}
+	return &incident, nil
+}
+
+// GetEscalatingIncidents returns the open, unacknowledged incidents that
+// follow an escalation policy
+func (s *MongoStore) GetEscalatingIncidents() ([]models.Incident, error) {
+	return s.findIncidents(bson.M{
+		"status":               models.IncidentOpen,
+		"acknowledged_at":      bson.M{"$in": bson.A{nil, 0}},
+		"escalation_policy_id": bson.M{"$nin": bson.A{nil, ""}},
+	}, 0)
+}
+
+// ClaimEscalation records that an incident's escalation advanced to step
+// at the given time. It only succeeds if the incident is still open,
+// unacknowledged and unchanged since it was read, so each notification is
+// claimed by exactly one instance.
+func (s *MongoStore) ClaimEscalation(incident models.Incident, step int, at int64, event *models.IncidentEvent) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":             incident.ID,
+		"status":          models.IncidentOpen,
+		"acknowledged_at": bson.M{"$in": bson.A{nil, 0}},
+		"escalation_step": incident.EscalationStep,
+	}
+	if incident.LastNotifiedAt == 0 {
+		filter["last_notified_at"] = bson.M{"$in": bson.A{nil, 0}}
+	} else {
+		filter["last_notified_at"] = incident.LastNotifiedAt
+	}
+	update := bson.M{"$set": bson.M{"escalation_step": step, "last_notified_at": at}}
+	if event != nil {
+		update["$push"] = bson.M{"timeline": *event}
+	}
+
+	result, err := s.incidentsColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("failed to update escalation of incident %s: %w", incident.ID, err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// GetLatestStatus returns the most recent status of a website, or nil if it has none
+func (s *MongoStore) GetLatestStatus(websiteID string) (*models.WebsiteStatus, error) {
+	var status models.WebsiteStatus
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.statusesColl.FindOne(
+		ctx,
+		bson.M{"website_id": websiteID},
+		options.FindOne().SetSort(bson.D{{Key: "checked_at", Value: -1}}),
+	).Decode(&status)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find latest status for website %s: %w", websiteID, err)
+	}
+	return &status, nil
+}
+
+// --- Maintenance Windows ---
+
+// SaveMaintenanceWindow inserts or replaces a maintenance window
+func (s *MongoStore) SaveMaintenanceWindow(window models.MaintenanceWindow) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.maintenanceColl.ReplaceOne(
+		ctx,
+		bson.M{"_id": window.ID, "user_id": window.UserID},
+		window,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save maintenance window %s: %w", window.Name, err)
+	}
+	return nil
+}
+
+// GetMaintenanceWindowsByUser returns a user's maintenance windows
+func (s *MongoStore) GetMaintenanceWindowsByUser(userID string) ([]models.MaintenanceWindow, error) {
+	windows := []models.MaintenanceWindow{}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := s.maintenanceColl.Find(ctx, bson.M{"user_id": userID}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find maintenance windows for user %s: %w", userID, err)
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("⚠️ Failed to close cursor: %v", err)
+		}
+	}()
+
+	if err := cursor.All(ctx, &windows); err != nil {
+		return nil, fmt.Errorf("failed to decode maintenance windows for user %s: %w", userID, err)
+	}
+	return windows, nil
+}
+
+// DeleteMaintenanceWindowByUser deletes a maintenance window only if it belongs to the user
+func (s *MongoStore) DeleteMaintenanceWindowByUser(id, userID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.maintenanceColl.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return fmt.Errorf("maintenance window not found or access denied")
+	}
+	return nil
+}
@@ -1,75 +1,195 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
 	"time"
 
 	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"github.com/prateeks007/PulseWatch/monitor/backend/utils"
+)
+
+const (
+	// fastRetryDelay is the pause before retrying a failed check
+	fastRetryDelay = 2 * time.Second
+	// defaultCheckTimeout is used when a website has no timeout set
+	defaultCheckTimeout = 30 * time.Second
+	// defaultMaxRedirects matches net/http's own redirect limit
+	defaultMaxRedirects = 10
 )
 
 // MonitorService checks websites
 type MonitorService struct {
 	client *http.Client
+	ssl    *SSLService
 }
 
-// NewMonitorService creates a new monitor service
-func NewMonitorService() *MonitorService {
+// NewMonitorService creates a new monitor service. TLS monitors perform
+// their handshake through ssl.
+func NewMonitorService(ssl *SSLService) *MonitorService {
 	return &MonitorService{
-		client: &http.Client{
-			Timeout: time.Second * 30, // Wait up to 30 seconds
-		},
+		// Timeouts are applied per request, see CheckTimeout
+		client: &http.Client{},
+		ssl:    ssl,
 	}
 }
 
-// CheckWebsite checks if a website is up
-func (s *MonitorService) CheckWebsite(website models.Website) (models.WebsiteStatus, error) {
-	// Record when we started
-	startTime := time.Now()
+// FastRetries returns how many immediate retries follow a failed request
+func FastRetries(website models.Website) int {
+	if website.Retries == nil || *website.Retries < 0 {
+		return 1
+	}
+	return *website.Retries
+}
+
+// CheckTimeout returns the request timeout for a website
+func CheckTimeout(website models.Website) time.Duration {
+	if website.Timeout <= 0 {
+		return defaultCheckTimeout
+	}
+	return time.Duration(website.Timeout) * time.Second
+}
+
+// clientFor returns a client applying the website's redirect policy
+func (s *MonitorService) clientFor(website models.Website) *http.Client {
+	client := *s.client
+	maxRedirects := website.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	follow := website.FollowRedirects == nil || *website.FollowRedirects
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !follow {
+			// Report the redirect response itself
+			return http.ErrUseLastResponse
+		}
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return &client
+}
 
+// CheckWithRetries checks a website and, if the check fails, retries it
+// right away up to FastRetries times so a single dropped connection
+// doesn't count as a failure. The returned status records the attempts made.
+func (s *MonitorService) CheckWithRetries(website models.Website) (models.WebsiteStatus, error) {
+	retries := FastRetries(website)
+	for attempt := 1; ; attempt++ {
+		status, err := s.CheckWebsite(website)
+		status.Attempts = attempt
+		if (err == nil && status.IsUp) || attempt > retries {
+			return status, err
+		}
+		time.Sleep(fastRetryDelay)
+	}
+}
+
+// CheckWebsite checks if a website is up using the checker for its type
+func (s *MonitorService) CheckWebsite(website models.Website) (models.WebsiteStatus, error) {
 	// Create a status object
 	status := models.WebsiteStatus{
 		WebsiteID: website.ID,
 		CheckedAt: time.Now().Unix(),
 	}
 
-	// Create request with User-Agent header
-	req, err := http.NewRequest("GET", website.URL, nil)
+	switch website.Type {
+	case "", models.MonitorHTTP:
+		return s.checkHTTP(website, status)
+	case models.MonitorTCP:
+		return s.checkTCP(website, status)
+	case models.MonitorDNS:
+		return s.checkDNS(website, status)
+	case models.MonitorTLS:
+		return s.checkTLS(website, status)
+	default:
+		return failedStatus(status, fmt.Errorf("unknown monitor type %q", website.Type))
+	}
+}
+
+// checkHTTP sends the website's HTTP request and evaluates the response
+func (s *MonitorService) checkHTTP(website models.Website, status models.WebsiteStatus) (models.WebsiteStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), CheckTimeout(website))
+	defer cancel()
+
+	// Build the request from the website's check definition
+	method := strings.ToUpper(website.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, website.URL, strings.NewReader(website.Body))
 	if err != nil {
 		status.IsUp = false
 		status.StatusCode = 0
+		status.Error = err.Error()
 		fmt.Printf("[DEBUG] %s request creation failed: %v\n", website.URL, err)
 		return status, err
 	}
 	req.Header.Set("User-Agent", "PulseWatch-Monitor/1.0")
-	
-	// Try to access the website
-	resp, err := s.client.Do(req)
+	for key, value := range website.Headers {
+		req.Header.Set(key, value)
+	}
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
 
-	// Calculate how long it took
-	responseTime := time.Since(startTime).Milliseconds()
-	status.ResponseTime = responseTime
+	// Trace the request so the response time can be broken down
+	timer := newRequestTimer()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+
+	// Try to access the website
+	resp, err := s.clientFor(website).Do(req)
 
 	// If there was an error, the site is down
 	if err != nil {
+		status.ResponseTime = timer.total().Milliseconds()
+		status.Timings = timer.timings()
 		status.IsUp = false
 		status.StatusCode = 0
+		status.Error = err.Error()
 		// 👇 log for debug with detailed error
-		fmt.Printf("[DEBUG] %s FAILED, respTime=%dms, error: %v\n", website.URL, responseTime, err)
+		fmt.Printf("[DEBUG] %s FAILED, respTime=%dms, error: %v\n", website.URL, status.ResponseTime, err)
 		return status, err
 	}
 	defer resp.Body.Close() // Always close the response
 
+	// Read the body so the response time includes the transfer, keeping
+	// it for assertions
+	limit := bodyReadLimit(website.Assertions)
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	timer.bodyDone()
+	truncated := int64(len(body)) > limit
+	if truncated {
+		body = body[:limit]
+	}
+
+	// Calculate how long it took
+	status.ResponseTime = timer.total().Milliseconds()
+	status.Timings = timer.timings()
+
 	// Record the HTTP status code
 	status.StatusCode = resp.StatusCode
 
-	// Site is "up" if status code indicates server is responding
-	// 200-399: Normal responses (up)
-	// 403: Forbidden but server is up
-	// 429: Rate limited but server is up
-	status.IsUp = (resp.StatusCode >= 200 && resp.StatusCode < 400) || 
-				  resp.StatusCode == 403 || 
-				  resp.StatusCode == 429
+	// Site is "up" if the status code is one the website accepts
+	status.IsUp = utils.StatusCodeAccepted(resp.StatusCode, website.AcceptedStatusCodes)
+
+	// An accepted response must also pass the website's body assertions
+	if status.IsUp && readErr != nil {
+		status.IsUp = false
+		status.Error = fmt.Sprintf("failed to read response body: %v", readErr)
+		return status, readErr
+	}
+	if status.IsUp && len(website.Assertions) > 0 {
+		if failure := CheckAssertions(website.Assertions, body, truncated); failure != "" {
+			status.IsUp = false
+			status.FailedAssertion = failure
+		}
+	}
 
 	if status.IsUp {
 		fmt.Printf("[DEBUG] %s UP %dms (Code: %d)\n", website.URL, status.ResponseTime, status.StatusCode)
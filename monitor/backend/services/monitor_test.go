@@ -0,0 +1,66 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestFastRetries(t *testing.T) {
+	retries := func(n int) *int { return &n }
+	tests := []struct {
+		retries *int
+		want    int
+	}{
+		{nil, 1},
+		{retries(-1), 1},
+		{retries(0), 0},
+		{retries(3), 3},
+	}
+	for _, tt := range tests {
+		if got := FastRetries(models.Website{Retries: tt.retries}); got != tt.want {
+			t.Errorf("FastRetries(%v) = %d, want %d", tt.retries, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWithRetries(t *testing.T) {
+	retries := func(n int) *int { return &n }
+	tests := []struct {
+		name         string
+		failures     int32 // Requests answered with a 500 before succeeding
+		retries      *int
+		wantUp       bool
+		wantAttempts int
+	}{
+		{name: "up right away", failures: 0, wantUp: true, wantAttempts: 1},
+		{name: "recovers on retry", failures: 1, wantUp: true, wantAttempts: 2},
+		{name: "retries disabled", failures: 1, retries: retries(0), wantUp: false, wantAttempts: 1},
+		{name: "down after every retry", failures: 2, wantUp: false, wantAttempts: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+
+			website := models.Website{ID: "w1", URL: server.URL, Retries: tt.retries}
+			status, _ := NewMonitorService(NewSSLService()).CheckWithRetries(website)
+			if status.IsUp != tt.wantUp || status.Attempts != tt.wantAttempts {
+				t.Errorf("CheckWithRetries() = up %v after %d attempts, want up %v after %d",
+					status.IsUp, status.Attempts, tt.wantUp, tt.wantAttempts)
+			}
+			if got := int(requests.Load()); got != tt.wantAttempts {
+				t.Errorf("server got %d requests, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// Alert is a notification about a change in a website's state
+type Alert struct {
+	Website models.Website       // Website the alert is about
+	Type    string               // One of the models.Alert* types
+	Status  models.WebsiteStatus // Check result that triggered the alert
+	Time    time.Time            // When the alert was raised
+
+	Incident    *models.Incident          // Incident the alert belongs to, if any
+	AckURL      string                    // One-click link acknowledging the incident, if any
+	Reminder    bool                      // Repeats a down alert for an unacknowledged incident
+	State       *models.MonitorState      // Monitor state after the check, if known
+	SSL         *models.SSLInfo           // Certificate check behind an SSL alert
+	Certificate *models.CertificateRecord // History record of a replaced certificate
+	Domain      *models.DomainInfo        // Registration behind a domain expiry alert
+
+	title string // Rendered title template of the channel, if any
+	body  string // Rendered body template of the channel, if any
+}
+
+// IsUp reports whether the alert announces a recovery, including a
+// website that stabilized while up or got fast again
+func (a Alert) IsUp() bool {
+	if a.Type == models.AlertStabilized {
+		return a.State != nil && a.State.IsUp
+	}
+	return a.Type == models.AlertUp || a.Type == models.AlertRestored
+}
+
+// IsWarning reports whether the alert warns of a problem while the
+// website is still up, such as slow responses or an expiring certificate
+// or domain
+func (a Alert) IsWarning() bool {
+	switch a.Type {
+	case models.AlertDegraded, models.AlertSSLExpiring, models.AlertSSLChanged, models.AlertSSLUntrusted, models.AlertDomainExpiring:
+		return true
+	}
+	return false
+}
+
+// Title returns a one-line summary of the alert
+func (a Alert) Title() string {
+	if a.title != "" {
+		return a.title
+	}
+	return a.defaultTitle()
+}
+
+// defaultTitle returns the title used when the channel has no title template
+func (a Alert) defaultTitle() string {
+	switch {
+	case a.Type == models.AlertFlapping:
+		return fmt.Sprintf("⚠️ %s is FLAPPING", a.Website.Name)
+	case a.Type == models.AlertStabilized && a.IsUp():
+		return fmt.Sprintf("✅ %s has stabilized and is ONLINE", a.Website.Name)
+	case a.Type == models.AlertStabilized:
+		return fmt.Sprintf("❌ %s has stabilized but is OFFLINE", a.Website.Name)
+	case a.Type == models.AlertDegraded:
+		return fmt.Sprintf("🐢 %s is DEGRADED", a.Website.Name)
+	case a.Type == models.AlertRestored:
+		return fmt.Sprintf("✅ %s is responding normally again", a.Website.Name)
+	case a.Type == models.AlertSSLExpiring && a.SSL != nil && a.SSL.DaysLeft < 1:
+		return fmt.Sprintf("🔒 %s's certificate expires today", a.Website.Name)
+	case a.Type == models.AlertSSLExpiring && a.SSL != nil && a.SSL.DaysLeft == 1:
+		return fmt.Sprintf("🔒 %s's certificate expires in 1 day", a.Website.Name)
+	case a.Type == models.AlertSSLExpiring && a.SSL != nil:
+		return fmt.Sprintf("🔒 %s's certificate expires in %d days", a.Website.Name, a.SSL.DaysLeft)
+	case a.Type == models.AlertSSLChanged:
+		return fmt.Sprintf("🔁 %s's certificate changed unexpectedly", a.Website.Name)
+	case a.Type == models.AlertSSLRevoked && a.SSL != nil && a.SSL.OCSPStatus == models.OCSPUnknown:
+		return fmt.Sprintf("❓ %s's certificate is unknown to its OCSP responder", a.Website.Name)
+	case a.Type == models.AlertSSLRevoked:
+		return fmt.Sprintf("⛔ %s's certificate is REVOKED", a.Website.Name)
+	case a.Type == models.AlertSSLError:
+		return fmt.Sprintf("🔓 %s's certificate check is failing", a.Website.Name)
+	case a.Type == models.AlertSSLUntrusted:
+		return fmt.Sprintf("🔓 %s's certificate isn't trusted", a.Website.Name)
+	case a.Type == models.AlertDomainExpiring && a.Domain != nil && a.Domain.DaysLeft < 1:
+		return fmt.Sprintf("📅 %s expires today", a.Domain.Domain)
+	case a.Type == models.AlertDomainExpiring && a.Domain != nil && a.Domain.DaysLeft == 1:
+		return fmt.Sprintf("📅 %s expires in 1 day", a.Domain.Domain)
+	case a.Type == models.AlertDomainExpiring && a.Domain != nil:
+		return fmt.Sprintf("📅 %s expires in %d days", a.Domain.Domain, a.Domain.DaysLeft)
+	}
+	if a.IsUp() {
+		return fmt.Sprintf("✅ %s is ONLINE", a.Website.Name)
+	}
+	if a.Reminder {
+		return fmt.Sprintf("⏰ %s is still OFFLINE", a.Website.Name)
+	}
+	return fmt.Sprintf("❌ %s is OFFLINE", a.Website.Name)
+}
+
+// AlertField is a labelled detail included in alert messages
+type AlertField struct {
+	Label string
+	Value string
+}
+
+// Downtime returns how long the website has been, or was, down
+func (a Alert) Downtime() time.Duration {
+	if a.Incident == nil {
+		return 0
+	}
+	if a.Incident.Status == models.IncidentResolved {
+		return time.Duration(a.Incident.DurationSeconds) * time.Second
+	}
+	return a.Time.Sub(time.Unix(a.Incident.StartedAt, 0))
+}
+
+// LastError returns the last failure reason seen during the incident
+func (a Alert) LastError() string {
+	if a.Incident != nil && a.Incident.LastError != "" {
+		return a.Incident.LastError
+	}
+	if !a.IsUp() {
+		return FailureReason(a.Status)
+	}
+	return ""
+}
+
+// Summary returns a one-sentence account of a recovery, such as
+// "Was down for 14m, last error: connection refused", or "" for down alerts
+func (a Alert) Summary() string {
+	if !a.IsUp() || a.Incident == nil {
+		return ""
+	}
+	summary := "Was down for " + FormatDuration(a.Downtime())
+	if lastError := a.LastError(); lastError != "" {
+		summary += ", last error: " + lastError
+	}
+	return summary
+}
+
+// Fields returns the details shown in the alert body
+func (a Alert) Fields() []AlertField {
+	var fields []AlertField
+	if a.Website.Type == models.MonitorPush {
+		// Never leak the ping token into alert channels
+		fields = append(fields, AlertField{Label: "Heartbeat", Value: fmt.Sprintf("every %s", CheckInterval(a.Website))})
+		if a.Status.JobDurationMs > 0 {
+			fields = append(fields, AlertField{Label: "Job Duration", Value: fmt.Sprintf("%dms", a.Status.JobDurationMs)})
+		}
+	} else {
+		fields = append(fields, AlertField{Label: "URL", Value: a.Website.URL})
+		if a.Status.ResponseTime > 0 {
+			fields = append(fields, AlertField{Label: "Response Time", Value: fmt.Sprintf("%dms", a.Status.ResponseTime)})
+		}
+	}
+	if a.Status.StatusCode != 0 {
+		fields = append(fields, AlertField{Label: "Status Code", Value: strconv.Itoa(a.Status.StatusCode)})
+	}
+	if a.Type == models.AlertFlapping && a.State != nil {
+		fields = append(fields, AlertField{
+			Label: "State Changes",
+			Value: fmt.Sprintf("%d%% of the last %d checks", TransitionRate(a.State.RecentResults), len(a.State.RecentResults)),
+		})
+	}
+	if a.Type == models.AlertDegraded || a.Type == models.AlertRestored {
+		fields = append(fields, AlertField{Label: "Threshold", Value: fmt.Sprintf("%dms", a.Website.DegradedThresholdMs)})
+		if p95 := WindowP95(a.State, a.Website); p95 > 0 {
+			fields = append(fields, AlertField{
+				Label: "p95",
+				Value: fmt.Sprintf("%dms over the last %d checks", p95, DegradedWindow(a.Website)),
+			})
+		}
+	}
+
+	if a.SSL != nil {
+		if a.SSL.ValidTo != 0 {
+			fields = append(fields, AlertField{Label: "Expires", Value: time.Unix(a.SSL.ValidTo, 0).UTC().Format("2006-01-02 15:04 MST")})
+		}
+		if a.SSL.Issuer != "" {
+			fields = append(fields, AlertField{Label: "Issuer", Value: a.SSL.Issuer})
+		}
+		if a.SSL.Error != "" {
+			fields = append(fields, AlertField{Label: "Error", Value: a.SSL.Error})
+		}
+		if a.SSL.VerificationError != "" {
+			fields = append(fields, AlertField{Label: "Verification", Value: a.SSL.VerificationError})
+		}
+		if a.Type == models.AlertSSLRevoked {
+			fields = append(fields, AlertField{Label: "OCSP Status", Value: fmt.Sprintf("%s (%s)", a.SSL.OCSPStatus, a.SSL.OCSPSource)})
+			if a.SSL.OCSPRevokedAt != 0 {
+				fields = append(fields, AlertField{Label: "Revoked", Value: time.Unix(a.SSL.OCSPRevokedAt, 0).UTC().Format("2006-01-02 15:04 MST")})
+			}
+			if a.SSL.OCSPRevocationReason != "" {
+				fields = append(fields, AlertField{Label: "Reason", Value: a.SSL.OCSPRevocationReason})
+			}
+		}
+	}
+	if a.Certificate != nil {
+		fields = append(fields, AlertField{Label: "Changes", Value: strings.Join(a.Certificate.Changes, "; ")})
+		fields = append(fields, AlertField{Label: "Fingerprint", Value: a.Certificate.Fingerprint})
+		if a.Certificate.PreviousFingerprint != "" {
+			fields = append(fields, AlertField{Label: "Previous Fingerprint", Value: a.Certificate.PreviousFingerprint})
+		}
+	}
+	if a.Domain != nil {
+		fields = append(fields, AlertField{Label: "Domain", Value: a.Domain.Domain})
+		fields = append(fields, AlertField{Label: "Expires", Value: time.Unix(a.Domain.ExpiresAt, 0).UTC().Format("2006-01-02 15:04 MST")})
+		if a.Domain.Registrar != "" {
+			fields = append(fields, AlertField{Label: "Registrar", Value: a.Domain.Registrar})
+		}
+	}
+
+	if a.IsUp() {
+		if a.Incident != nil {
+			fields = append(fields, AlertField{Label: "Downtime", Value: FormatDuration(a.Downtime())})
+		}
+		if lastError := a.LastError(); lastError != "" {
+			fields = append(fields, AlertField{Label: "Last Error", Value: lastError})
+		}
+		return fields
+	}
+
+	if a.Status.FailedAssertion != "" {
+		fields = append(fields, AlertField{Label: "Reason", Value: a.Status.FailedAssertion})
+	}
+	if a.Status.Error != "" {
+		fields = append(fields, AlertField{Label: "Error", Value: a.Status.Error})
+	}
+	if a.Reminder && a.Incident != nil {
+		fields = append(fields, AlertField{Label: "Down For", Value: FormatDuration(a.Downtime())})
+	}
+	if a.AckURL != "" {
+		fields = append(fields, AlertField{Label: "Acknowledge", Value: a.AckURL})
+	}
+	return fields
+}
+
+// Message returns the plain-text body of the alert
+func (a Alert) Message() string {
+	if a.body != "" {
+		return a.body
+	}
+	return a.defaultMessage()
+}
+
+// defaultMessage returns the body used when the channel has no body template
+func (a Alert) defaultMessage() string {
+	fields := a.Fields()
+	lines := make([]string, 0, len(fields)+1)
+	if summary := a.Summary(); summary != "" {
+		lines = append(lines, summary)
+	}
+	for _, field := range fields {
+		lines = append(lines, field.Label+": "+field.Value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatDuration formats a duration for people, e.g. "45s", "14m" or "2h 5m"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// Notifier delivers alerts to a single notification channel
+type Notifier interface {
+	Notify(alert Alert) error
+}
+
+// NotificationService builds notifiers for user channels and fans alerts out to them
+type NotificationService struct {
+	client         *http.Client
+	discord        *DiscordService
+	telegramAPIURL string
+}
+
+// NewNotificationService creates a notification service. Discord channels
+// are delivered through the given DiscordService.
+func NewNotificationService(discord *DiscordService) *NotificationService {
+	telegramAPIURL := os.Getenv("TELEGRAM_API_URL")
+	if telegramAPIURL == "" {
+		telegramAPIURL = "https://api.telegram.org"
+	}
+	return &NotificationService{
+		client:         &http.Client{Timeout: 10 * time.Second},
+		discord:        discord,
+		telegramAPIURL: strings.TrimRight(telegramAPIURL, "/"),
+	}
+}
+
+// NotifierFor returns the notifier for a channel
+func (n *NotificationService) NotifierFor(channel models.NotificationChannel) (Notifier, error) {
+	switch channel.Type {
+	case models.ChannelDiscord:
+		return &discordNotifier{discord: n.discord, webhookURL: channel.URL}, nil
+	case models.ChannelSlack:
+		return &slackNotifier{client: n.client, webhookURL: channel.URL}, nil
+	case models.ChannelTeams:
+		return &teamsNotifier{client: n.client, webhookURL: channel.URL}, nil
+	case models.ChannelTelegram:
+		apiURL := n.telegramAPIURL
+		if channel.URL != "" {
+			apiURL = strings.TrimRight(channel.URL, "/")
+		}
+		return &telegramNotifier{client: n.client, apiURL: apiURL, token: channel.Token, chatID: channel.ChatID}, nil
+	case models.ChannelEmail:
+		return newEmailNotifier(channel), nil
+	case models.ChannelNtfy:
+		return &ntfyNotifier{client: n.client, serverURL: strings.TrimRight(channel.URL, "/"), topic: channel.Topic, token: channel.Token}, nil
+	case models.ChannelGotify:
+		return &gotifyNotifier{client: n.client, serverURL: strings.TrimRight(channel.URL, "/"), token: channel.Token}, nil
+	case models.ChannelWebhook:
+		return &webhookNotifier{client: n.client, url: channel.URL, headers: channel.Headers}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification channel type %q", channel.Type)
+	}
+}
+
+// Notify sends an alert to every enabled channel and returns the combined
+// error of the channels that failed
+func (n *NotificationService) Notify(channels []models.NotificationChannel, alert Alert) error {
+	var errs []error
+	for _, channel := range channels {
+		if channel.Disabled {
+			continue
+		}
+		notifier, err := n.NotifierFor(channel)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		channelAlert, err := alert.RenderTemplates(channel)
+		if err != nil {
+			// A broken template must not swallow the alert
+			errs = append(errs, fmt.Errorf("%s channel %q: %w", channel.Type, channel.Name, err))
+		}
+		if err := notifier.Notify(channelAlert); err != nil {
+			errs = append(errs, fmt.Errorf("%s channel %q: %w", channel.Type, channel.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UserChannels returns every channel a user's alerts go to, including the
+// legacy Discord webhook setting
+func UserChannels(user *models.User) []models.NotificationChannel {
+	if user == nil {
+		return nil
+	}
+	channels := make([]models.NotificationChannel, 0, len(user.NotificationChannels)+1)
+	if user.DiscordWebhookURL != "" {
+		channels = append(channels, models.NotificationChannel{
+			ID:   "discord",
+			Type: models.ChannelDiscord,
+			Name: "Discord",
+			URL:  user.DiscordWebhookURL,
+		})
+	}
+	return append(channels, user.NotificationChannels...)
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestAlertFields(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	website := models.Website{Name: "Example", URL: "https://example.com"}
+	tests := []struct {
+		name  string
+		alert Alert
+		want  []AlertField
+	}{
+		{
+			name: "down",
+			alert: Alert{
+				Website: website,
+				Type:    models.AlertDown,
+				Status:  models.WebsiteStatus{StatusCode: 500, ResponseTime: 120, FailedAssertion: `body contains "ok"`},
+				AckURL:  "https://pulsewatch.example.com/ack",
+			},
+			want: []AlertField{
+				{Label: "URL", Value: "https://example.com"},
+				{Label: "Response Time", Value: "120ms"},
+				{Label: "Status Code", Value: "500"},
+				{Label: "Reason", Value: `body contains "ok"`},
+				{Label: "Acknowledge", Value: "https://pulsewatch.example.com/ack"},
+			},
+		},
+		{
+			name: "down without a response",
+			alert: Alert{
+				Website: website,
+				Type:    models.AlertDown,
+				Status:  models.WebsiteStatus{Error: "connection refused"},
+			},
+			want: []AlertField{
+				{Label: "URL", Value: "https://example.com"},
+				{Label: "Error", Value: "connection refused"},
+			},
+		},
+		{
+			name: "reminder",
+			alert: Alert{
+				Website:  website,
+				Type:     models.AlertDown,
+				Status:   models.WebsiteStatus{Error: "connection refused"},
+				Time:     now,
+				Incident: &models.Incident{Status: models.IncidentOpen, StartedAt: now.Add(-90 * time.Minute).Unix()},
+				Reminder: true,
+			},
+			want: []AlertField{
+				{Label: "URL", Value: "https://example.com"},
+				{Label: "Error", Value: "connection refused"},
+				{Label: "Down For", Value: "1h 30m"},
+			},
+		},
+		{
+			name: "up",
+			alert: Alert{
+				Website:  website,
+				Type:     models.AlertUp,
+				Status:   models.WebsiteStatus{StatusCode: 200, ResponseTime: 80},
+				Incident: &models.Incident{Status: models.IncidentResolved, DurationSeconds: 840, LastError: "connection refused"},
+			},
+			want: []AlertField{
+				{Label: "URL", Value: "https://example.com"},
+				{Label: "Response Time", Value: "80ms"},
+				{Label: "Status Code", Value: "200"},
+				{Label: "Downtime", Value: "14m"},
+				{Label: "Last Error", Value: "connection refused"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.alert.Fields(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Fields() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAlertFieldsPush(t *testing.T) {
+	alert := Alert{
+		Website: models.Website{Type: models.MonitorPush, URL: "secret-token", Interval: 300},
+		Type:    models.AlertDown,
+		Status:  models.WebsiteStatus{JobDurationMs: 4200, ResponseTime: 99},
+	}
+	want := []AlertField{
+		{Label: "Heartbeat", Value: "every 5m0s"},
+		{Label: "Job Duration", Value: "4200ms"},
+	}
+	if got := alert.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAlertSummary(t *testing.T) {
+	resolved := &models.Incident{Status: models.IncidentResolved, DurationSeconds: 840, LastError: "connection refused"}
+	tests := []struct {
+		name  string
+		alert Alert
+		want  string
+	}{
+		{"recovery", Alert{Type: models.AlertUp, Incident: resolved}, "Was down for 14m, last error: connection refused"},
+		{"recovery without an error", Alert{Type: models.AlertUp, Incident: &models.Incident{Status: models.IncidentResolved, DurationSeconds: 45}}, "Was down for 45s"},
+		{"recovery without an incident", Alert{Type: models.AlertUp}, ""},
+		{"down", Alert{Type: models.AlertDown, Incident: resolved}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.alert.Summary(); got != tt.want {
+			t.Errorf("%s: Summary() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAlertMessage(t *testing.T) {
+	alert := Alert{
+		Website:  models.Website{Name: "Example", URL: "https://example.com"},
+		Type:     models.AlertUp,
+		Incident: &models.Incident{Status: models.IncidentResolved, DurationSeconds: 840, LastError: "HTTP 502"},
+	}
+	want := "Was down for 14m, last error: HTTP 502\nURL: https://example.com\nDowntime: 14m\nLast Error: HTTP 502"
+	if got := alert.Message(); got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45*time.Second + 400*time.Millisecond, "45s"},
+		{14*time.Minute + 59*time.Second, "14m"},
+		{2*time.Hour + 5*time.Minute, "2h 5m"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookRecoveryPayload(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusOK)
+	alert := Alert{
+		Website:  models.Website{ID: "w1", Name: "Example", URL: "https://example.com"},
+		Type:     models.AlertUp,
+		Status:   models.WebsiteStatus{StatusCode: 200},
+		Time:     time.Unix(1700000000, 0),
+		Incident: &models.Incident{ID: "i1", Status: models.IncidentResolved, DurationSeconds: 840, LastError: "connection refused"},
+	}
+	channel := models.NotificationChannel{Type: models.ChannelWebhook, URL: server.URL}
+	if err := NewNotificationService(nil).Notify([]models.NotificationChannel{channel}, alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var payload struct {
+		IncidentID      string `json:"incident_id"`
+		DowntimeSeconds int64  `json:"downtime_seconds"`
+		LastError       string `json:"last_error"`
+		Summary         string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte((<-requests).body), &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.IncidentID != "i1" || payload.DowntimeSeconds != 840 || payload.LastError != "connection refused" ||
+		payload.Summary != "Was down for 14m, last error: connection refused" {
+		t.Errorf("payload = %+v, want the incident's downtime and last error", payload)
+	}
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// discordNotifier delivers alerts through DiscordService
+type discordNotifier struct {
+	discord    *DiscordService
+	webhookURL string
+}
+
+func (d *discordNotifier) Notify(alert Alert) error {
+	return d.discord.SendAlertEmbed(d.webhookURL, alert)
+}
+
+// slackNotifier posts to a Slack incoming webhook
+type slackNotifier struct {
+	client     *http.Client
+	webhookURL string
+}
+
+func (s *slackNotifier) Notify(alert Alert) error {
+	payload := map[string]interface{}{
+		"text": fmt.Sprintf("*%s*\n%s", alert.Title(), alert.Message()),
+	}
+	return postJSON(s.client, s.webhookURL, payload, nil)
+}
+
+// teamsNotifier posts a MessageCard to a Microsoft Teams webhook
+type teamsNotifier struct {
+	client     *http.Client
+	webhookURL string
+}
+
+func (t *teamsNotifier) Notify(alert Alert) error {
+	color := "FF0000"
+	if alert.IsUp() {
+		color = "00FF00"
+	} else if alert.IsWarning() {
+		color = "FFA500"
+	}
+	payload := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": color,
+		"summary":    alert.Title(),
+		"title":      alert.Title(),
+		"text":       strings.ReplaceAll(alert.Message(), "\n", "<br>"),
+	}
+	return postJSON(t.client, t.webhookURL, payload, nil)
+}
+
+// telegramNotifier sends a message through the Telegram Bot API
+type telegramNotifier struct {
+	client *http.Client
+	apiURL string
+	token  string
+	chatID string
+}
+
+func (t *telegramNotifier) Notify(alert Alert) error {
+	payload := map[string]interface{}{
+		"chat_id": t.chatID,
+		"text":    alert.Title() + "\n" + alert.Message(),
+	}
+	return postJSON(t.client, fmt.Sprintf("%s/bot%s/sendMessage", t.apiURL, t.token), payload, nil)
+}
+
+// emailNotifier sends alerts over SMTP
+type emailNotifier struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+}
+
+func newEmailNotifier(channel models.NotificationChannel) *emailNotifier {
+	port := channel.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	return &emailNotifier{
+		addr:     fmt.Sprintf("%s:%d", channel.SMTPHost, port),
+		host:     channel.SMTPHost,
+		username: channel.Username,
+		password: channel.Password,
+		from:     channel.From,
+		to:       channel.To,
+	}
+}
+
+func (e *emailNotifier) Notify(alert Alert) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", alert.Title()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.Time.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(alert.Message(), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if e.username != "" {
+		auth = smtp.PlainAuth("", e.username, e.password, e.host)
+	}
+	if err := smtp.SendMail(e.addr, auth, e.from, e.to, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// ntfyNotifier publishes to an ntfy topic
+type ntfyNotifier struct {
+	client    *http.Client
+	serverURL string
+	topic     string
+	token     string
+}
+
+func (n *ntfyNotifier) Notify(alert Alert) error {
+	req, err := http.NewRequest("POST", n.serverURL+"/"+url.PathEscape(n.topic), strings.NewReader(alert.Message()))
+	if err != nil {
+		return fmt.Errorf("failed to create ntfy request: %w", err)
+	}
+	req.Header.Set("Title", alert.Title())
+	switch {
+	case alert.IsUp():
+		req.Header.Set("Tags", "white_check_mark")
+	case alert.IsWarning():
+		req.Header.Set("Tags", "warning")
+	default:
+		req.Header.Set("Priority", "high")
+		req.Header.Set("Tags", "rotating_light")
+	}
+	if alert.AckURL != "" {
+		req.Header.Set("Actions", "view, Acknowledge, "+alert.AckURL)
+	}
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+	return doRequest(n.client, req)
+}
+
+// gotifyNotifier sends a Gotify application message
+type gotifyNotifier struct {
+	client    *http.Client
+	serverURL string
+	token     string
+}
+
+func (g *gotifyNotifier) Notify(alert Alert) error {
+	priority := 8
+	if alert.IsUp() || alert.IsWarning() {
+		priority = 5
+	}
+	payload := map[string]interface{}{
+		"title":    alert.Title(),
+		"message":  alert.Message(),
+		"priority": priority,
+	}
+	return postJSON(g.client, g.serverURL+"/message", payload, map[string]string{"X-Gotify-Key": g.token})
+}
+
+// webhookNotifier posts a generic JSON payload to any URL
+type webhookNotifier struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+func (w *webhookNotifier) Notify(alert Alert) error {
+	websiteURL := alert.Website.URL
+	if alert.Website.Type == models.MonitorPush {
+		websiteURL = "" // Never leak the ping token
+	}
+	payload := map[string]interface{}{
+		"event":   alert.Type,
+		"title":   alert.Title(),
+		"message": alert.Message(),
+		"website": map[string]interface{}{
+			"id":   alert.Website.ID,
+			"name": alert.Website.Name,
+			"url":  websiteURL,
+			"type": MonitorType(alert.Website),
+		},
+		"status":    alert.Status,
+		"timestamp": alert.Time.Format(time.RFC3339),
+	}
+	if alert.Incident != nil {
+		payload["incident_id"] = alert.Incident.ID
+		payload["downtime_seconds"] = int64(alert.Downtime().Seconds())
+	}
+	if lastError := alert.LastError(); lastError != "" {
+		payload["last_error"] = lastError
+	}
+	if summary := alert.Summary(); summary != "" {
+		payload["summary"] = summary
+	}
+	if alert.AckURL != "" {
+		payload["ack_url"] = alert.AckURL
+	}
+	if alert.SSL != nil {
+		payload["ssl"] = alert.SSL
+	}
+	if alert.Certificate != nil {
+		payload["certificate"] = alert.Certificate
+	}
+	if alert.Domain != nil {
+		payload["domain"] = alert.Domain
+	}
+	return postJSON(w.client, w.url, payload, w.headers)
+}
+
+// postJSON sends payload as JSON and expects a 2xx response
+func postJSON(client *http.Client, target string, payload interface{}, headers map[string]string) error {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	req, err := http.NewRequest("POST", target, bytes.NewReader(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return doRequest(client, req)
+}
+
+// doRequest sends req and turns non-2xx responses into errors
+func doRequest(client *http.Client, req *http.Request) error {
+	req.Header.Set("User-Agent", "PulseWatch-Monitor/1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("request returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
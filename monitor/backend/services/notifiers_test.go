@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// recordedRequest is a request received by a notifier stand-in
+type recordedRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+// newRecordingServer starts a stand-in that records every request and
+// answers with status
+func newRecordingServer(t *testing.T, status int) (*httptest.Server, <-chan recordedRequest) {
+	t.Helper()
+	requests := make(chan recordedRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- recordedRequest{path: r.URL.Path, header: r.Header, body: string(body)}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func testAlert() Alert {
+	return Alert{
+		Website: models.Website{ID: "w1", Name: "Example", URL: "https://example.com"},
+		Type:    models.AlertDown,
+		Status:  models.WebsiteStatus{WebsiteID: "w1", StatusCode: 503, CheckedAt: 1700000000},
+		Time:    time.Unix(1700000000, 0),
+		AckURL:  "https://pulsewatch.example.com/ack",
+	}
+}
+
+func TestNotifiersPost(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel models.NotificationChannel
+		path    string
+		headers map[string]string
+		body    []string // Substrings the body must contain
+	}{
+		{
+			name:    "discord",
+			channel: models.NotificationChannel{Type: models.ChannelDiscord},
+			path:    "/",
+			body:    []string{`"embeds"`, "Example"},
+		},
+		{
+			name:    "slack",
+			channel: models.NotificationChannel{Type: models.ChannelSlack},
+			path:    "/",
+			body:    []string{`"text"`, "Example"},
+		},
+		{
+			name:    "teams",
+			channel: models.NotificationChannel{Type: models.ChannelTeams},
+			path:    "/",
+			body:    []string{`"@type":"MessageCard"`, `"themeColor":"FF0000"`},
+		},
+		{
+			name:    "telegram",
+			channel: models.NotificationChannel{Type: models.ChannelTelegram, Token: "123:abc", ChatID: "42"},
+			path:    "/bot123:abc/sendMessage",
+			body:    []string{`"chat_id":"42"`, "Example"},
+		},
+		{
+			name:    "ntfy",
+			channel: models.NotificationChannel{Type: models.ChannelNtfy, Topic: "alerts", Token: "tk"},
+			path:    "/alerts",
+			headers: map[string]string{
+				"Authorization": "Bearer tk",
+				"Priority":      "high",
+				"Actions":       "view, Acknowledge, https://pulsewatch.example.com/ack",
+			},
+			body: []string{"https://example.com", "503"},
+		},
+		{
+			name:    "gotify",
+			channel: models.NotificationChannel{Type: models.ChannelGotify, Token: "app"},
+			path:    "/message",
+			headers: map[string]string{"X-Gotify-Key": "app"},
+			body:    []string{`"priority":8`},
+		},
+		{
+			name:    "webhook",
+			channel: models.NotificationChannel{Type: models.ChannelWebhook, Headers: map[string]string{"X-Secret": "s"}},
+			path:    "/",
+			headers: map[string]string{"X-Secret": "s", "Content-Type": "application/json"},
+			body:    []string{`"event":"down"`, `"ack_url":"https://pulsewatch.example.com/ack"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Discord answers webhooks with 204, the others accept any 2xx
+			server, requests := newRecordingServer(t, http.StatusNoContent)
+			tt.channel.Name = tt.name
+			tt.channel.URL = server.URL
+			notifications := NewNotificationService(NewDiscordService())
+			if err := notifications.Notify([]models.NotificationChannel{tt.channel}, testAlert()); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+
+			var req recordedRequest
+			select {
+			case req = <-requests:
+			default:
+				t.Fatal("no request was sent")
+			}
+			if req.path != tt.path {
+				t.Errorf("path = %q, want %q", req.path, tt.path)
+			}
+			for key, want := range tt.headers {
+				if got := req.header.Get(key); got != want {
+					t.Errorf("header %s = %q, want %q", key, got, want)
+				}
+			}
+			for _, want := range tt.body {
+				if !strings.Contains(req.body, want) {
+					t.Errorf("body %s does not contain %s", req.body, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyReportsFailures(t *testing.T) {
+	server, _ := newRecordingServer(t, http.StatusInternalServerError)
+	notifications := NewNotificationService(NewDiscordService())
+	channels := []models.NotificationChannel{
+		{Type: models.ChannelSlack, Name: "failing", URL: server.URL},
+		{Type: models.ChannelSlack, Name: "disabled", URL: server.URL, Disabled: true},
+	}
+	err := notifications.Notify(channels, testAlert())
+	if err == nil || !strings.Contains(err.Error(), `"failing"`) || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("Notify() error = %v, want the failing channel's status", err)
+	}
+	if err != nil && strings.Contains(err.Error(), "disabled") {
+		t.Errorf("Notify() sent to a disabled channel: %v", err)
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	server, requests := newRecordingServer(t, http.StatusNoContent)
+	alert := testAlert()
+	alert.Website.Type = models.MonitorPush
+	alert.Website.URL = "/api/push/secret-token"
+	channel := models.NotificationChannel{Type: models.ChannelWebhook, URL: server.URL}
+	if err := NewNotificationService(nil).Notify([]models.NotificationChannel{channel}, alert); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	var payload struct {
+		Event   string `json:"event"`
+		Website struct {
+			URL  string `json:"url"`
+			Type string `json:"type"`
+		} `json:"website"`
+	}
+	if err := json.Unmarshal([]byte((<-requests).body), &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Event != models.AlertDown || payload.Website.Type != models.MonitorPush {
+		t.Errorf("payload = %+v, want a down alert for a push monitor", payload)
+	}
+	if payload.Website.URL != "" {
+		t.Errorf("payload leaks the ping URL %q", payload.Website.URL)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// A minimal SMTP stand-in that accepts one message
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch strings.ToUpper(strings.Fields(line)[0]) {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "DATA":
+				inData = true
+				reply("354 Go ahead")
+			case "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	channel := models.NotificationChannel{
+		Type:     models.ChannelEmail,
+		SMTPHost: "127.0.0.1",
+		SMTPPort: addr.Port,
+		From:     "alerts@example.com",
+		To:       []string{"ops@example.com", "oncall@example.com"},
+	}
+	if err := NewNotificationService(nil).Notify([]models.NotificationChannel{channel}, testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	select {
+	case message := <-received:
+		for _, want := range []string{"From: alerts@example.com", "To: ops@example.com, oncall@example.com", "Subject: ", "Example"} {
+			if !strings.Contains(message, want) {
+				t.Errorf("message does not contain %q:\n%s", want, message)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message was received")
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	// maxOCSPResponseSize bounds how much of a responder's answer is read
+	maxOCSPResponseSize = 64 << 10
+	// minOCSPCacheTime is the least time a responder's answer is reused
+	// for, so responders aren't asked on every check
+	minOCSPCacheTime = time.Hour
+)
+
+// cachedOCSP is a responder's answer kept until it expires
+type cachedOCSP struct {
+	response *ocsp.Response
+	expires  time.Time
+}
+
+// ocspRevocationReasons names the RFC 5280 revocation reason codes
+var ocspRevocationReasons = map[int]string{
+	ocsp.Unspecified:          "unspecified",
+	ocsp.KeyCompromise:        "key_compromise",
+	ocsp.CACompromise:         "ca_compromise",
+	ocsp.AffiliationChanged:   "affiliation_changed",
+	ocsp.Superseded:           "superseded",
+	ocsp.CessationOfOperation: "cessation_of_operation",
+	ocsp.CertificateHold:      "certificate_hold",
+	ocsp.RemoveFromCRL:        "remove_from_crl",
+	ocsp.PrivilegeWithdrawn:   "privilege_withdrawn",
+	ocsp.AACompromise:         "aa_compromise",
+}
+
+// checkOCSP records the revocation status of a chain's leaf on info. A
+// fresh stapled response is used when the server sent one; otherwise the
+// responder named in the certificate is asked, and its answer reused
+// until its next update.
+func (s *SSLService) checkOCSP(info *models.SSLInfo, certs []*x509.Certificate, stapled []byte, now time.Time) {
+	if len(certs) < 2 {
+		info.OCSPError = "issuer certificate not served"
+		return
+	}
+	leaf, issuer := certs[0], certs[1]
+
+	if len(stapled) > 0 {
+		response, err := ocsp.ParseResponseForCert(stapled, leaf, issuer)
+		if err == nil && !ocspStale(response, now) {
+			applyOCSPResponse(info, response, "stapled", now)
+			return
+		}
+	}
+
+	if len(leaf.OCSPServer) == 0 {
+		info.OCSPError = "certificate names no OCSP responder"
+		return
+	}
+	fingerprint := CertificateFingerprint(leaf)
+	response := s.cachedOCSP(fingerprint, now)
+	if response == nil {
+		var err error
+		if response, err = s.queryOCSP(leaf.OCSPServer[0], leaf, issuer); err != nil {
+			info.OCSPError = err.Error()
+			return
+		}
+		s.cacheOCSP(fingerprint, response, now)
+	}
+	applyOCSPResponse(info, response, "responder", now)
+}
+
+// cachedOCSP returns the responder's unexpired answer for a certificate, or nil
+func (s *SSLService) cachedOCSP(fingerprint string, now time.Time) *ocsp.Response {
+	s.ocspMu.Lock()
+	defer s.ocspMu.Unlock()
+	cached, ok := s.ocspCache[fingerprint]
+	if !ok || !now.Before(cached.expires) {
+		return nil
+	}
+	return cached.response
+}
+
+// cacheOCSP keeps a responder's answer until its next update, but at least
+// minOCSPCacheTime, dropping answers that have expired
+func (s *SSLService) cacheOCSP(fingerprint string, response *ocsp.Response, now time.Time) {
+	expires := now.Add(minOCSPCacheTime)
+	if response.NextUpdate.After(expires) {
+		expires = response.NextUpdate
+	}
+
+	s.ocspMu.Lock()
+	defer s.ocspMu.Unlock()
+	for key, cached := range s.ocspCache {
+		if !now.Before(cached.expires) {
+			delete(s.ocspCache, key)
+		}
+	}
+	s.ocspCache[fingerprint] = cachedOCSP{response: response, expires: expires}
+}
+
+// queryOCSP asks an OCSP responder for the status of cert
+func (s *SSLService) queryOCSP(server string, cert, issuer *x509.Certificate) (*ocsp.Response, error) {
+	request, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCSP request: %w", err)
+	}
+	resp, err := s.client.Post(server, "application/ocsp-request", bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("OCSP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder returned HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxOCSPResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OCSP response: %w", err)
+	}
+	response, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCSP response: %w", err)
+	}
+	return response, nil
+}
+
+// applyOCSPResponse records a parsed OCSP response on info
+func applyOCSPResponse(info *models.SSLInfo, response *ocsp.Response, source string, now time.Time) {
+	switch response.Status {
+	case ocsp.Good:
+		info.OCSPStatus = models.OCSPGood
+	case ocsp.Revoked:
+		info.OCSPStatus = models.OCSPRevoked
+		info.OCSPRevokedAt = response.RevokedAt.Unix()
+		info.OCSPRevocationReason = ocspRevocationReasons[response.RevocationReason]
+	default:
+		info.OCSPStatus = models.OCSPUnknown
+	}
+	info.OCSPSource = source
+	info.OCSPThisUpdate = response.ThisUpdate.Unix()
+	if !response.NextUpdate.IsZero() {
+		info.OCSPNextUpdate = response.NextUpdate.Unix()
+	}
+	info.OCSPStale = ocspStale(response, now)
+}
+
+// ocspStale reports whether a response is past its next update
+func ocspStale(response *ocsp.Response, now time.Time) bool {
+	return !response.NextUpdate.IsZero() && now.After(response.NextUpdate)
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspResponder is a local OCSP responder answering for one issuer
+type ocspResponder struct {
+	issuer   *testCert
+	template ocsp.Response // Status and times to answer with
+	requests atomic.Int32
+}
+
+func (o *ocspResponder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	o.requests.Add(1)
+	body, _ := io.ReadAll(r.Body)
+	request, err := ocsp.ParseRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response := o.template
+	response.SerialNumber = request.SerialNumber
+	der, err := ocsp.CreateResponse(o.issuer.cert, o.issuer.cert, response, o.issuer.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	w.Write(der)
+}
+
+// newOCSPChain returns a chain whose leaf names a local responder
+func newOCSPChain(t *testing.T) (testChain, *ocspResponder) {
+	t.Helper()
+	responder := &ocspResponder{}
+	server := httptest.NewServer(responder)
+	t.Cleanup(server.Close)
+	chain := newTestChain(t, testCertOptions{}, testCertOptions{ocspServer: server.URL})
+	responder.issuer = chain.intermediate
+	return chain, responder
+}
+
+// staple returns a stapled response for the chain's leaf
+func staple(t *testing.T, chain testChain, template ocsp.Response) []byte {
+	t.Helper()
+	template.SerialNumber = chain.leaf.cert.SerialNumber
+	der, err := ocsp.CreateResponse(chain.intermediate.cert, chain.intermediate.cert, template, chain.intermediate.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestCheckOCSP(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	good := ocsp.Response{Status: ocsp.Good, ThisUpdate: now.Add(-time.Hour), NextUpdate: now.Add(24 * time.Hour)}
+	stale := ocsp.Response{Status: ocsp.Good, ThisUpdate: now.Add(-48 * time.Hour), NextUpdate: now.Add(-24 * time.Hour)}
+	revoked := ocsp.Response{
+		Status:           ocsp.Revoked,
+		ThisUpdate:       now.Add(-time.Hour),
+		NextUpdate:       now.Add(24 * time.Hour),
+		RevokedAt:        now.Add(-2 * time.Hour),
+		RevocationReason: ocsp.KeyCompromise,
+	}
+
+	tests := []struct {
+		name         string
+		stapled      *ocsp.Response // Response stapled by the server, if any
+		responder    ocsp.Response  // Response of the responder
+		want         string
+		wantSource   string
+		wantStale    bool
+		wantReason   string
+		wantRequests int32
+	}{
+		{
+			name:       "fresh staple",
+			stapled:    &good,
+			responder:  revoked,
+			want:       models.OCSPGood,
+			wantSource: "stapled",
+		},
+		{
+			name:         "responder",
+			responder:    good,
+			want:         models.OCSPGood,
+			wantSource:   "responder",
+			wantRequests: 1,
+		},
+		{
+			name:         "revoked",
+			responder:    revoked,
+			want:         models.OCSPRevoked,
+			wantSource:   "responder",
+			wantReason:   "key_compromise",
+			wantRequests: 1,
+		},
+		{
+			name:         "unknown",
+			responder:    ocsp.Response{Status: ocsp.Unknown, ThisUpdate: now.Add(-time.Hour)},
+			want:         models.OCSPUnknown,
+			wantSource:   "responder",
+			wantRequests: 1,
+		},
+		{
+			name:         "stale staple falls back to the responder",
+			stapled:      &stale,
+			responder:    good,
+			want:         models.OCSPGood,
+			wantSource:   "responder",
+			wantRequests: 1,
+		},
+		{
+			name:         "stale responder",
+			responder:    stale,
+			want:         models.OCSPGood,
+			wantSource:   "responder",
+			wantStale:    true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, responder := newOCSPChain(t)
+			responder.template = tt.responder
+			var stapled []byte
+			if tt.stapled != nil {
+				stapled = staple(t, chain, *tt.stapled)
+			}
+
+			info := &models.SSLInfo{}
+			NewSSLService().checkOCSP(info, []*x509.Certificate{chain.leaf.cert, chain.intermediate.cert}, stapled, now)
+			if info.OCSPError != "" {
+				t.Fatalf("OCSPError = %q", info.OCSPError)
+			}
+			if info.OCSPStatus != tt.want || info.OCSPSource != tt.wantSource || info.OCSPStale != tt.wantStale {
+				t.Errorf("OCSP = %s from %s (stale %v), want %s from %s (stale %v)",
+					info.OCSPStatus, info.OCSPSource, info.OCSPStale, tt.want, tt.wantSource, tt.wantStale)
+			}
+			if info.OCSPRevocationReason != tt.wantReason {
+				t.Errorf("OCSPRevocationReason = %q, want %q", info.OCSPRevocationReason, tt.wantReason)
+			}
+			if tt.want == models.OCSPRevoked && info.OCSPRevokedAt != revoked.RevokedAt.Unix() {
+				t.Errorf("OCSPRevokedAt = %d, want %d", info.OCSPRevokedAt, revoked.RevokedAt.Unix())
+			}
+			if got := responder.requests.Load(); got != tt.wantRequests {
+				t.Errorf("responder asked %d times, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestCheckOCSPErrors(t *testing.T) {
+	chain := newTestChain(t, testCertOptions{}, testCertOptions{})
+	tests := []struct {
+		name  string
+		certs []*x509.Certificate
+		want  string
+	}{
+		{"issuer not served", []*x509.Certificate{chain.leaf.cert}, "issuer certificate not served"},
+		{"no responder", []*x509.Certificate{chain.leaf.cert, chain.intermediate.cert}, "certificate names no OCSP responder"},
+	}
+	for _, tt := range tests {
+		info := &models.SSLInfo{}
+		NewSSLService().checkOCSP(info, tt.certs, nil, time.Now())
+		if info.OCSPError != tt.want || info.OCSPStatus != "" {
+			t.Errorf("%s: OCSPError = %q with status %q, want %q", tt.name, info.OCSPError, info.OCSPStatus, tt.want)
+		}
+	}
+}
+
+func TestCheckOCSPCache(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	chain, responder := newOCSPChain(t)
+	certs := []*x509.Certificate{chain.leaf.cert, chain.intermediate.cert}
+	s := NewSSLService()
+	check := func(at time.Time) *models.SSLInfo {
+		info := &models.SSLInfo{}
+		s.checkOCSP(info, certs, nil, at)
+		return info
+	}
+
+	// Reused until the next update
+	responder.template = ocsp.Response{Status: ocsp.Good, ThisUpdate: now, NextUpdate: now.Add(6 * time.Hour)}
+	check(now)
+	check(now.Add(5 * time.Hour))
+	if got := responder.requests.Load(); got != 1 {
+		t.Fatalf("responder asked %d times before the next update, want 1", got)
+	}
+	responder.template.Status = ocsp.Revoked
+	responder.template.NextUpdate = now.Add(6*time.Hour + 10*time.Minute)
+	if info := check(now.Add(6 * time.Hour)); info.OCSPStatus != models.OCSPRevoked {
+		t.Errorf("OCSPStatus after the next update = %q, want %q", info.OCSPStatus, models.OCSPRevoked)
+	}
+
+	// Kept for at least minOCSPCacheTime even if the next update is sooner
+	if info := check(now.Add(6*time.Hour + 30*time.Minute)); info.OCSPStatus != models.OCSPRevoked {
+		t.Errorf("OCSPStatus = %q, want the cached %q", info.OCSPStatus, models.OCSPRevoked)
+	}
+	if got := responder.requests.Load(); got != 2 {
+		t.Errorf("responder asked %d times, want 2", got)
+	}
+
+	// Failed lookups aren't cached
+	responder.issuer = newTestCert(t, testCertOptions{name: "Someone Else", isCA: true}, nil)
+	if info := check(now.Add(8 * time.Hour)); info.OCSPError == "" {
+		t.Errorf("OCSPError is empty for a response signed by the wrong issuer")
+	}
+	check(now.Add(8 * time.Hour))
+	if got := responder.requests.Load(); got != 4 {
+		t.Errorf("responder asked %d times, want failed lookups retried", got)
+	}
+}
+
+func TestApplySSLResultOCSP(t *testing.T) {
+	const validTo = 1800000000
+	ocspCheck := func(status string) models.SSLInfo {
+		return models.SSLInfo{ValidTo: validTo, DaysLeft: 90, OCSPStatus: status}
+	}
+	tests := []struct {
+		name   string
+		checks []models.SSLInfo
+		want   []string
+	}{
+		{
+			name:   "good never alerts",
+			checks: []models.SSLInfo{ocspCheck(models.OCSPGood), ocspCheck(models.OCSPGood)},
+			want:   []string{"", ""},
+		},
+		{
+			name:   "revoked alerts once",
+			checks: []models.SSLInfo{ocspCheck(models.OCSPRevoked), ocspCheck(models.OCSPRevoked), ocspCheck("")},
+			want:   []string{models.AlertSSLRevoked, "", ""},
+		},
+		{
+			name: "unknown then revoked alerts both",
+			checks: []models.SSLInfo{
+				ocspCheck(models.OCSPUnknown), ocspCheck(models.OCSPRevoked),
+			},
+			want: []string{models.AlertSSLRevoked, models.AlertSSLRevoked},
+		},
+		{
+			name: "alerts again after being good",
+			checks: []models.SSLInfo{
+				ocspCheck(models.OCSPUnknown), ocspCheck(models.OCSPGood), ocspCheck(models.OCSPUnknown),
+			},
+			want: []string{models.AlertSSLRevoked, "", models.AlertSSLRevoked},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &models.MonitorState{}
+			var got []string
+			for _, info := range tt.checks {
+				got = append(got, ApplySSLResult(state, info, defaultSSLExpiryThresholds))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alerts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+const (
+	// DefaultCheckInterval is used when a website has no interval set
+	DefaultCheckInterval = 60 * time.Second
+	// MinCheckInterval is the shortest interval the scheduler will honor
+	MinCheckInterval = 30 * time.Second
+	// maxStartJitter caps how long a new job waits before its first check
+	maxStartJitter = 30 * time.Second
+)
+
+// SchedulerService runs one check loop per website based on its Interval
+type SchedulerService struct {
+	mu    sync.Mutex
+	jobs  map[string]*scheduledJob
+	check func(models.Website)
+}
+
+// scheduledJob is a single website's check loop
+type scheduledJob struct {
+	website models.Website
+	stop    chan struct{}
+}
+
+// NewSchedulerService creates a scheduler that calls check for every due website
+func NewSchedulerService(check func(models.Website)) *SchedulerService {
+	return &SchedulerService{
+		jobs:  make(map[string]*scheduledJob),
+		check: check,
+	}
+}
+
+// CheckInterval returns the effective check interval for a website
+func CheckInterval(website models.Website) time.Duration {
+	if website.Interval <= 0 {
+		return DefaultCheckInterval
+	}
+	interval := time.Duration(website.Interval) * time.Second
+	if interval < MinCheckInterval {
+		return MinCheckInterval
+	}
+	return interval
+}
+
+// scheduleInterval returns how often the scheduler runs a website's check.
+// Push monitors are evaluated more often than their expected period so a
+// missed ping is noticed soon after its grace window ends.
+func scheduleInterval(website models.Website) time.Duration {
+	if website.Type == models.MonitorPush {
+		return MinCheckInterval
+	}
+	return CheckInterval(website)
+}
+
+// Sync reconciles running jobs with the given websites: new websites are
+// scheduled, changed ones are restarted and missing ones are stopped
+func (s *SchedulerService) Sync(websites []models.Website) {
+	seen := make(map[string]bool, len(websites))
+	for _, website := range websites {
+		seen[website.ID] = true
+		s.Schedule(website)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.jobs {
+		if !seen[id] {
+			close(job.stop)
+			delete(s.jobs, id)
+			fmt.Printf("🗓️ Unscheduled %s\n", job.website.Name)
+		}
+	}
+}
+
+// Schedule starts a check loop for a website, restarting it if the
+// website's definition changed since it was scheduled
+func (s *SchedulerService) Schedule(website models.Website) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[website.ID]; ok {
+		if job.website.URL == website.URL && scheduleInterval(job.website) == scheduleInterval(website) {
+			job.website = website
+			return
+		}
+		close(job.stop)
+	}
+
+	job := &scheduledJob{website: website, stop: make(chan struct{})}
+	s.jobs[website.ID] = job
+	interval := scheduleInterval(website)
+	go s.run(job, interval)
+	fmt.Printf("🗓️ Scheduled %s every %s\n", website.Name, interval)
+}
+
+// Unschedule stops the check loop for a website
+func (s *SchedulerService) Unschedule(websiteID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[websiteID]; ok {
+		close(job.stop)
+		delete(s.jobs, websiteID)
+	}
+}
+
+// Len returns the number of scheduled websites
+func (s *SchedulerService) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs)
+}
+
+// Stop stops every check loop
+func (s *SchedulerService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, job := range s.jobs {
+		close(job.stop)
+		delete(s.jobs, id)
+	}
+}
+
+// run waits a random jitter so that websites added together don't all
+// fire at once, then checks the website every interval until stopped.
+// The interval is passed in since job.website is only safe to read
+// under the lock.
+func (s *SchedulerService) run(job *scheduledJob, interval time.Duration) {
+	jitter := interval
+	if jitter > maxStartJitter {
+		jitter = maxStartJitter
+	}
+
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(jitter)))):
+	case <-job.stop:
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.check(s.current(job))
+		select {
+		case <-ticker.C:
+		case <-job.stop:
+			return
+		}
+	}
+}
+
+// current returns the latest known definition of a job's website
+func (s *SchedulerService) current(job *scheduledJob) models.Website {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return job.website
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestCheckInterval(t *testing.T) {
+	tests := []struct {
+		website models.Website
+		want    time.Duration
+	}{
+		{models.Website{}, DefaultCheckInterval},
+		{models.Website{Interval: -5}, DefaultCheckInterval},
+		{models.Website{Interval: 10}, MinCheckInterval},
+		{models.Website{Interval: 30}, 30 * time.Second},
+		{models.Website{Interval: 300}, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := CheckInterval(tt.website); got != tt.want {
+			t.Errorf("CheckInterval(%d) = %s, want %s", tt.website.Interval, got, tt.want)
+		}
+	}
+
+	push := models.Website{Type: models.MonitorPush, Interval: 3600}
+	if got := scheduleInterval(push); got != MinCheckInterval {
+		t.Errorf("scheduleInterval(push) = %s, want %s", got, MinCheckInterval)
+	}
+	if got := CheckInterval(push); got != time.Hour {
+		t.Errorf("CheckInterval(push) = %s, want the expected ping period", got)
+	}
+}
+
+// stopped reports whether a job's check loop was told to stop
+func stopped(job *scheduledJob) bool {
+	select {
+	case <-job.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	website := models.Website{ID: "w1", Name: "Example", URL: "https://example.com", Interval: 60}
+	tests := []struct {
+		name        string
+		change      func(models.Website) models.Website
+		wantRestart bool
+	}{
+		{
+			name:   "unchanged",
+			change: func(w models.Website) models.Website { return w },
+		},
+		{
+			name:   "renamed",
+			change: func(w models.Website) models.Website { w.Name = "Renamed"; return w },
+		},
+		{
+			name:        "new URL",
+			change:      func(w models.Website) models.Website { w.URL = "https://example.org"; return w },
+			wantRestart: true,
+		},
+		{
+			name:        "new interval",
+			change:      func(w models.Website) models.Website { w.Interval = 120; return w },
+			wantRestart: true,
+		},
+		{
+			name:   "interval clamped to the same schedule",
+			change: func(w models.Website) models.Website { w.Interval = 0; return w },
+		},
+		{
+			name:        "push monitor",
+			change:      func(w models.Website) models.Website { w.Type = models.MonitorPush; w.Interval = 3600; return w },
+			wantRestart: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchedulerService(func(models.Website) {})
+			defer s.Stop()
+			s.Schedule(website)
+			first := s.jobs[website.ID]
+
+			changed := tt.change(website)
+			s.Schedule(changed)
+			job := s.jobs[website.ID]
+			if restarted := job != first; restarted != tt.wantRestart {
+				t.Fatalf("restarted = %v, want %v", restarted, tt.wantRestart)
+			}
+			if stopped(first) != tt.wantRestart || stopped(job) {
+				t.Errorf("old loop stopped = %v, new loop stopped = %v", stopped(first), stopped(job))
+			}
+			if s.current(job).Name != changed.Name || s.Len() != 1 {
+				t.Errorf("job runs %+v (%d jobs), want the latest definition", s.current(job), s.Len())
+			}
+		})
+	}
+}
+
+func TestSync(t *testing.T) {
+	s := NewSchedulerService(func(models.Website) {})
+	defer s.Stop()
+	a := models.Website{ID: "a", URL: "https://a.example.com"}
+	b := models.Website{ID: "b", URL: "https://b.example.com"}
+	c := models.Website{ID: "c", URL: "https://c.example.com"}
+
+	s.Sync([]models.Website{a, b})
+	jobA, jobB := s.jobs["a"], s.jobs["b"]
+	if s.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", s.Len())
+	}
+
+	s.Sync([]models.Website{a, c})
+	if s.Len() != 2 || s.jobs["b"] != nil || s.jobs["c"] == nil {
+		t.Errorf("jobs = %v, want a and c", s.jobs)
+	}
+	if !stopped(jobB) {
+		t.Errorf("deleted website's loop is still running")
+	}
+	if s.jobs["a"] != jobA || stopped(jobA) {
+		t.Errorf("unchanged website was restarted")
+	}
+
+	jobC := s.jobs["c"]
+	s.Unschedule("c")
+	s.Unschedule("missing")
+	if s.Len() != 1 || !stopped(jobC) {
+		t.Errorf("Unschedule() left %d jobs, stopped = %v", s.Len(), stopped(jobC))
+	}
+
+	s.Stop()
+	if s.Len() != 0 || !stopped(jobA) {
+		t.Errorf("Stop() left %d jobs", s.Len())
+	}
+}
@@ -2,20 +2,41 @@ package services
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prateeks007/PulseWatch/monitor/backend/models"
 )
 
-type SSLService struct{}
+// sslDialTimeout bounds certificate checks that aren't tied to a website
+const sslDialTimeout = 10 * time.Second
 
-func NewSSLService() *SSLService { return &SSLService{} }
+type SSLService struct {
+	client    *http.Client // Used for OCSP responders
+	ocspMu    sync.Mutex
+	ocspCache map[string]cachedOCSP // Responder answers by leaf fingerprint
+}
+
+func NewSSLService() *SSLService {
+	return &SSLService{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		ocspCache: make(map[string]cachedOCSP),
+	}
+}
 
 func (s *SSLService) Check(hostURL string) (*models.SSLInfo, error) {
+	return s.checkURL(hostURL, sslDialTimeout)
+}
+
+// checkURL checks the certificate of an HTTPS URL's host, giving up on
+// the handshake after timeout
+func (s *SSLService) checkURL(hostURL string, timeout time.Duration) (*models.SSLInfo, error) {
 	u, err := url.Parse(hostURL)
 	if err != nil || u.Host == "" {
 		return nil, fmt.Errorf("invalid url")
@@ -24,13 +45,48 @@ func (s *SSLService) Check(hostURL string) (*models.SSLInfo, error) {
 	if !strings.Contains(host, ":") {
 		host = host + ":443"
 	}
-	dialer := &net.Dialer{Timeout: 10 * time.Second}
-	conn, err := tls.DialWithDialer(dialer, "tcp", host, &tls.Config{
-		ServerName: strings.Split(u.Host, ":")[0],
-	})
+	info, err := s.CheckTarget(host, strings.Split(u.Host, ":")[0], models.TLSDirect, timeout)
+	if info != nil {
+		info.Host = u.Host
+	}
+	return info, err
+}
+
+// CheckWebsite checks the certificate a website serves: the URL's host for
+// HTTPS websites, or the host:port target of TLS monitors, within the
+// website's check timeout
+func (s *SSLService) CheckWebsite(website models.Website) (*models.SSLInfo, error) {
+	switch website.Type {
+	case "", models.MonitorHTTP:
+		return s.checkURL(website.URL, CheckTimeout(website))
+	case models.MonitorTLS:
+		protocol := TLSProtocol(website)
+		host, port, err := SplitTarget(website.URL, TLSDefaultPort(protocol))
+		if err != nil {
+			return nil, err
+		}
+		return s.CheckTarget(net.JoinHostPort(host, port), host, protocol, CheckTimeout(website))
+	default:
+		return nil, fmt.Errorf("%s monitors have no certificate", website.Type)
+	}
+}
+
+// CheckAddress performs a direct TLS handshake with addr (host:port) and
+// reads the chain presented for serverName
+func (s *SSLService) CheckAddress(addr, serverName string) (*models.SSLInfo, error) {
+	return s.CheckTarget(addr, serverName, models.TLSDirect, sslDialTimeout)
+}
+
+// CheckTarget connects to addr (host:port), upgrades to TLS as protocol
+// requires (one of the models.TLS* protocols) and reads the chain
+// presented for serverName, giving up after timeout. The chain is
+// verified separately so its details are recorded even when it isn't
+// trusted; Error is only set if the handshake itself failed.
+func (s *SSLService) CheckTarget(addr, serverName, protocol string, timeout time.Duration) (*models.SSLInfo, error) {
+	conn, err := dialTLS(addr, serverName, protocol, timeout)
 	if err != nil {
 		return &models.SSLInfo{
-			Host:      u.Host,
+			Host:      addr,
 			Error:     err.Error(),
 			CheckedAt: time.Now().Unix(),
 		}, nil
@@ -40,27 +96,45 @@ func (s *SSLService) Check(hostURL string) (*models.SSLInfo, error) {
 	cs := conn.ConnectionState()
 	if len(cs.PeerCertificates) == 0 {
 		return &models.SSLInfo{
-			Host:      u.Host,
+			Host:      addr,
 			Error:     "no peer certificates",
 			CheckedAt: time.Now().Unix(),
 		}, nil
 	}
-	cert := cs.PeerCertificates[0]
-	validFrom := cert.NotBefore.Unix()
-	validTo := cert.NotAfter.Unix()
-	daysLeft := int(time.Until(cert.NotAfter).Hours() / 24)
+	now := time.Now()
+	info := DescribeConnection(addr, serverName, cs, nil, now)
+	s.checkOCSP(info, cs.PeerCertificates, cs.OCSPResponse, now)
+	return info, nil
+}
 
-	issuer := cert.Issuer.CommonName
-	if issuer == "" {
-		issuer = cert.Issuer.String()
+// DescribeConnection builds the SSL info of a TLS connection, verifying
+// its chain for serverName against roots (the system roots if nil)
+func DescribeConnection(addr, serverName string, cs tls.ConnectionState, roots *x509.CertPool, now time.Time) *models.SSLInfo {
+	cert := cs.PeerCertificates[0]
+	leaf := DescribeCertificate(cert)
+	info := &models.SSLInfo{
+		Host:               addr,
+		ValidFrom:          leaf.ValidFrom,
+		ValidTo:            leaf.ValidTo,
+		Issuer:             leaf.Issuer,
+		CheckedAt:          now.Unix(),
+		DaysLeft:           int(cert.NotAfter.Sub(now).Hours() / 24),
+		Subject:            leaf.Subject,
+		SANs:               leaf.SANs,
+		KeyType:            leaf.KeyType,
+		KeySize:            leaf.KeySize,
+		SignatureAlgorithm: leaf.SignatureAlgorithm,
+		Fingerprint:        leaf.Fingerprint,
+		TLSVersion:         tls.VersionName(cs.Version),
+		CipherSuite:        tls.CipherSuiteName(cs.CipherSuite),
+	}
+	for _, c := range cs.PeerCertificates {
+		info.Chain = append(info.Chain, DescribeCertificate(c))
 	}
 
-	return &models.SSLInfo{
-		Host:      u.Host,
-		ValidFrom: validFrom,
-		ValidTo:   validTo,
-		Issuer:    issuer,
-		CheckedAt: time.Now().Unix(),
-		DaysLeft:  daysLeft,
-	}, nil
+	reason, message := VerifyChain(cs.PeerCertificates, serverName, roots, now)
+	info.Verified = reason == ""
+	info.VerificationReason = reason
+	info.VerificationError = message
+	return info
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// defaultSSLExpiryThresholds are the days before expiry certificates are
+// alerted at when the user hasn't chosen their own
+var defaultSSLExpiryThresholds = []int{30, 14, 7, 1}
+
+// SSLExpiryThresholds returns the user's expiry thresholds in days, largest first
+func SSLExpiryThresholds(user *models.User) []int {
+	if user == nil || len(user.SSLExpiryThresholds) == 0 {
+		return defaultSSLExpiryThresholds
+	}
+	return sortedThresholds(user.SSLExpiryThresholds)
+}
+
+// sortedThresholds returns a copy of thresholds, largest first
+func sortedThresholds(thresholds []int) []int {
+	sorted := append([]int(nil), thresholds...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+	return sorted
+}
+
+// crossedThreshold returns the lowest of thresholds (largest first) that
+// daysLeft is within, or 0 if there is none or it isn't below alerted,
+// the threshold alerted last
+func crossedThreshold(thresholds []int, daysLeft, alerted int) int {
+	crossed := 0
+	for _, threshold := range thresholds {
+		if daysLeft <= threshold {
+			crossed = threshold
+		}
+	}
+	if alerted != 0 && crossed >= alerted {
+		return 0
+	}
+	return crossed
+}
+
+// ServesTLS reports whether a website's certificate is worth alerting on:
+// HTTPS websites and TLS monitors
+func ServesTLS(website models.Website) bool {
+	switch website.Type {
+	case "", models.MonitorHTTP:
+		return strings.HasPrefix(strings.ToLower(website.URL), "https://")
+	case models.MonitorTLS:
+		return true
+	}
+	return false
+}
+
+// ApplySSLResult records a certificate check on a monitor's state and
+// returns the alert that should be sent for it, or "" if none is due.
+// An error is alerted once until a check succeeds again, and so is a
+// revoked or unknown OCSP status until it is good again. Expiry is alerted
+// once per threshold crossed (largest first); when several are crossed
+// between checks only the lowest is sent. A renewed certificate starts
+// over. A chain that doesn't verify is still checked for revocation and
+// expiry, which take precedence; its failure is alerted once until it
+// verifies again.
+func ApplySSLResult(state *models.MonitorState, info models.SSLInfo, thresholds []int) string {
+	if info.Error != "" {
+		if state.SSLError != "" {
+			return ""
+		}
+		state.SSLError = info.Error
+		return models.AlertSSLError
+	}
+	state.SSLError = ""
+
+	if info.ValidTo != state.SSLValidTo {
+		state.SSLValidTo = info.ValidTo
+		state.SSLAlertedThreshold = 0
+		state.SSLOCSPStatus = ""
+	}
+
+	switch info.OCSPStatus {
+	case models.OCSPRevoked, models.OCSPUnknown:
+		if state.SSLOCSPStatus != info.OCSPStatus {
+			state.SSLOCSPStatus = info.OCSPStatus
+			return models.AlertSSLRevoked
+		}
+	case models.OCSPGood:
+		state.SSLOCSPStatus = ""
+	}
+
+	if crossed := crossedThreshold(thresholds, info.DaysLeft, state.SSLAlertedThreshold); crossed != 0 {
+		state.SSLAlertedThreshold = crossed
+		return models.AlertSSLExpiring
+	}
+
+	if info.VerificationReason == "" {
+		state.SSLUntrusted = ""
+		return ""
+	}
+	if state.SSLUntrusted == info.VerificationReason {
+		return ""
+	}
+	state.SSLUntrusted = info.VerificationReason
+	return models.AlertSSLUntrusted
+}
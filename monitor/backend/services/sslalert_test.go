@@ -0,0 +1,102 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestApplySSLResult(t *testing.T) {
+	const validTo, renewedTo = 1800000000, 1900000000
+	check := func(daysLeft int) models.SSLInfo {
+		return models.SSLInfo{ValidTo: validTo, DaysLeft: daysLeft}
+	}
+	tests := []struct {
+		name   string
+		checks []models.SSLInfo
+		want   []string // Alert returned for each check
+	}{
+		{
+			name:   "far from expiry",
+			checks: []models.SSLInfo{check(90), check(60)},
+			want:   []string{"", ""},
+		},
+		{
+			name:   "each threshold alerts once",
+			checks: []models.SSLInfo{check(31), check(30), check(29), check(14), check(10), check(7)},
+			want:   []string{"", models.AlertSSLExpiring, "", models.AlertSSLExpiring, "", models.AlertSSLExpiring},
+		},
+		{
+			name:   "skipped thresholds send the lowest once",
+			checks: []models.SSLInfo{check(40), check(5), check(3)},
+			want:   []string{"", models.AlertSSLExpiring, ""},
+		},
+		{
+			name: "renewal starts over",
+			checks: []models.SSLInfo{
+				check(5),
+				{ValidTo: renewedTo, DaysLeft: 90},
+				{ValidTo: renewedTo, DaysLeft: 29},
+			},
+			want: []string{models.AlertSSLExpiring, "", models.AlertSSLExpiring},
+		},
+		{
+			name: "errors alert once until they clear",
+			checks: []models.SSLInfo{
+				{Error: "handshake failed"},
+				{Error: "handshake failed"},
+				{Error: "connection refused"},
+				check(90),
+				{Error: "handshake failed"},
+			},
+			want: []string{models.AlertSSLError, "", "", "", models.AlertSSLError},
+		},
+		{
+			name: "untrusted certificates still alert on expiry",
+			checks: []models.SSLInfo{
+				{ValidTo: validTo, DaysLeft: 3, VerificationReason: models.SSLSelfSigned},
+				{ValidTo: validTo, DaysLeft: 3, VerificationReason: models.SSLSelfSigned},
+				{ValidTo: validTo, DaysLeft: 2, VerificationReason: models.SSLSelfSigned},
+			},
+			want: []string{models.AlertSSLExpiring, models.AlertSSLUntrusted, ""},
+		},
+		{
+			name: "untrusted alerts once per reason until it verifies",
+			checks: []models.SSLInfo{
+				{ValidTo: validTo, DaysLeft: 90, VerificationReason: models.SSLHostnameMismatch},
+				{ValidTo: validTo, DaysLeft: 90, VerificationReason: models.SSLHostnameMismatch},
+				{ValidTo: validTo, DaysLeft: 90, VerificationReason: models.SSLUnknownAuthority},
+				check(90),
+				{ValidTo: validTo, DaysLeft: 90, VerificationReason: models.SSLUnknownAuthority},
+			},
+			want: []string{models.AlertSSLUntrusted, "", models.AlertSSLUntrusted, "", models.AlertSSLUntrusted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &models.MonitorState{}
+			var got []string
+			for _, info := range tt.checks {
+				got = append(got, ApplySSLResult(state, info, defaultSSLExpiryThresholds))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alerts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSLExpiryThresholds(t *testing.T) {
+	if got := SSLExpiryThresholds(nil); !reflect.DeepEqual(got, defaultSSLExpiryThresholds) {
+		t.Errorf("SSLExpiryThresholds(nil) = %v, want the defaults", got)
+	}
+	user := &models.User{SSLExpiryThresholds: []int{3, 45, 10}}
+	if got, want := SSLExpiryThresholds(user), []int{45, 10, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SSLExpiryThresholds() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(user.SSLExpiryThresholds, []int{3, 45, 10}) {
+		t.Errorf("SSLExpiryThresholds() reordered the user's settings: %v", user.SSLExpiryThresholds)
+	}
+}
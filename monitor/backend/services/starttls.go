@@ -0,0 +1,272 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// tlsDefaultPorts are the ports TLS monitors connect to when the target has none
+var tlsDefaultPorts = map[string]string{
+	models.TLSDirect:   "443",
+	models.TLSSMTP:     "25",
+	models.TLSIMAP:     "143",
+	models.TLSPOP3:     "110",
+	models.TLSLDAP:     "389",
+	models.TLSPostgres: "5432",
+}
+
+// TLSProtocol returns the protocol a TLS monitor speaks before its
+// handshake: its TLSProtocol, else its target's scheme (e.g. smtp://),
+// else direct TLS
+func TLSProtocol(website models.Website) string {
+	if website.TLSProtocol != "" {
+		return strings.ToLower(website.TLSProtocol)
+	}
+	if i := strings.Index(website.URL, "://"); i != -1 {
+		if scheme := strings.ToLower(website.URL[:i]); tlsDefaultPorts[scheme] != "" {
+			return scheme
+		}
+	}
+	return models.TLSDirect
+}
+
+// TLSDefaultPort returns the port a TLS protocol is usually served on
+func TLSDefaultPort(protocol string) string {
+	if port, ok := tlsDefaultPorts[protocol]; ok {
+		return port
+	}
+	return tlsDefaultPorts[models.TLSDirect]
+}
+
+// dialTLS connects to addr, upgrades the connection as protocol requires
+// and completes a TLS handshake for serverName. The chain isn't verified.
+func dialTLS(addr, serverName, protocol string, timeout time.Duration) (*tls.Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if err := startTLS(conn, protocol); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true, // Verified separately to report why it fails
+	})
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// startTLS asks the server on conn to switch to TLS
+func startTLS(conn net.Conn, protocol string) error {
+	reader := bufio.NewReader(conn)
+	switch protocol {
+	case models.TLSDirect:
+		return nil
+	case models.TLSSMTP:
+		if _, err := readSMTPReply(reader, "220"); err != nil {
+			return fmt.Errorf("smtp greeting: %w", err)
+		}
+		if _, err := fmt.Fprintf(conn, "EHLO pulsewatch\r\n"); err != nil {
+			return err
+		}
+		lines, err := readSMTPReply(reader, "250")
+		if err != nil {
+			return fmt.Errorf("smtp EHLO: %w", err)
+		}
+		if !containsFold(lines, "STARTTLS") {
+			return fmt.Errorf("smtp server does not offer STARTTLS")
+		}
+		if _, err := fmt.Fprintf(conn, "STARTTLS\r\n"); err != nil {
+			return err
+		}
+		if _, err := readSMTPReply(reader, "220"); err != nil {
+			return fmt.Errorf("smtp STARTTLS: %w", err)
+		}
+		return nil
+	case models.TLSIMAP:
+		line, err := readLine(reader)
+		if err != nil || !strings.HasPrefix(line, "* OK") {
+			return fmt.Errorf("imap greeting: %s", lineOrError(line, err))
+		}
+		if _, err := fmt.Fprintf(conn, "a1 STARTTLS\r\n"); err != nil {
+			return err
+		}
+		for {
+			line, err := readLine(reader)
+			if err != nil {
+				return fmt.Errorf("imap STARTTLS: %w", err)
+			}
+			if strings.HasPrefix(line, "a1 ") {
+				if !strings.HasPrefix(line, "a1 OK") {
+					return fmt.Errorf("imap STARTTLS: %s", line)
+				}
+				return nil
+			}
+		}
+	case models.TLSPOP3:
+		line, err := readLine(reader)
+		if err != nil || !strings.HasPrefix(line, "+OK") {
+			return fmt.Errorf("pop3 greeting: %s", lineOrError(line, err))
+		}
+		if _, err := fmt.Fprintf(conn, "STLS\r\n"); err != nil {
+			return err
+		}
+		line, err = readLine(reader)
+		if err != nil || !strings.HasPrefix(line, "+OK") {
+			return fmt.Errorf("pop3 STLS: %s", lineOrError(line, err))
+		}
+		return nil
+	case models.TLSLDAP:
+		return startLDAPTLS(conn, reader)
+	case models.TLSPostgres:
+		// SSLRequest: length 8, then the magic code 1234.5679
+		request := make([]byte, 8)
+		binary.BigEndian.PutUint32(request[0:4], 8)
+		binary.BigEndian.PutUint32(request[4:8], 80877103)
+		if _, err := conn.Write(request); err != nil {
+			return err
+		}
+		answer, err := reader.ReadByte()
+		if err != nil {
+			return fmt.Errorf("postgres SSLRequest: %w", err)
+		}
+		if answer != 'S' {
+			return fmt.Errorf("postgres server does not accept SSL")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported TLS protocol %q", protocol)
+	}
+}
+
+// ldapStartTLSOID names the LDAP StartTLS extended operation
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// startLDAPTLS sends an LDAP StartTLS extended request and checks that
+// the server's extended response reports success
+func startLDAPTLS(conn net.Conn, reader *bufio.Reader) error {
+	// LDAPMessage { messageID 1, ExtendedRequest [APPLICATION 23] { requestName [0] OID } }
+	name := append([]byte{0x80, byte(len(ldapStartTLSOID))}, ldapStartTLSOID...)
+	op := append([]byte{0x77, byte(len(name))}, name...)
+	body := append([]byte{0x02, 0x01, 0x01}, op...)
+	message := append([]byte{0x30, byte(len(body))}, body...)
+	if _, err := conn.Write(message); err != nil {
+		return err
+	}
+
+	// LDAPMessage { messageID, ExtendedResponse [APPLICATION 24] { resultCode ENUMERATED, ... } }
+	tag, response, err := readBER(reader)
+	if err != nil || tag != 0x30 {
+		return fmt.Errorf("ldap StartTLS: invalid response")
+	}
+	// Skip the message ID to reach the protocol operation
+	if len(response) < 2 || response[0] != 0x02 || len(response) < 2+int(response[1]) {
+		return fmt.Errorf("ldap StartTLS: invalid response")
+	}
+	op = response[2+int(response[1]):]
+	if len(op) < 2 || op[0] != 0x78 {
+		return fmt.Errorf("ldap StartTLS: unexpected response")
+	}
+	_, opBody, err := readBER(bufio.NewReader(bytes.NewReader(op)))
+	if err != nil || len(opBody) < 3 || opBody[0] != 0x0a || opBody[1] != 0x01 {
+		return fmt.Errorf("ldap StartTLS: invalid response")
+	}
+	if code := opBody[2]; code != 0 {
+		return fmt.Errorf("ldap StartTLS: result code %d", code)
+	}
+	return nil
+}
+
+// readBER reads one BER element, returning its tag and contents
+func readBER(reader *bufio.Reader) (byte, []byte, error) {
+	tag, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	first, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		// Long form: the low bits give how many length bytes follow
+		count := int(first & 0x7f)
+		if count == 0 || count > 3 {
+			return 0, nil, fmt.Errorf("unsupported BER length")
+		}
+		length = 0
+		for i := 0; i < count; i++ {
+			b, err := reader.ReadByte()
+			if err != nil {
+				return 0, nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	contents := make([]byte, length)
+	if _, err := io.ReadFull(reader, contents); err != nil {
+		return 0, nil, err
+	}
+	return tag, contents, nil
+}
+
+// readSMTPReply reads a possibly multi-line SMTP reply and checks its code
+func readSMTPReply(reader *bufio.Reader, code string) ([]string, error) {
+	var lines []string
+	for {
+		line, err := readLine(reader)
+		if err != nil {
+			return lines, err
+		}
+		if len(line) < 3 || line[:3] != code {
+			return lines, fmt.Errorf("unexpected reply %q", line)
+		}
+		lines = append(lines, line[3:])
+		// "250-" continues the reply, "250 " ends it
+		if len(line) == 3 || line[3] != '-' {
+			return lines, nil
+		}
+	}
+}
+
+// readLine reads a CRLF or LF terminated line without its terminator
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// lineOrError describes an unexpected line, or the error reading it
+func lineOrError(line string, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("unexpected reply %q", line)
+}
+
+// containsFold reports whether any of lines starts with the keyword word,
+// ignoring case
+func containsFold(lines []string, word string) bool {
+	for _, line := range lines {
+		fields := strings.Fields(strings.TrimLeft(line, "- "))
+		if len(fields) > 0 && strings.EqualFold(fields[0], word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// newStartTLSServer starts a stand-in that runs preamble on each
+// connection and, if it returns true, completes a TLS handshake with cert
+func newStartTLSServer(t *testing.T, cert *testCert, preamble func(conn net.Conn, reader *bufio.Reader) bool) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	config := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{cert.cert.Raw}, PrivateKey: cert.key}}}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.SetDeadline(time.Now().Add(5 * time.Second))
+				if preamble(conn, bufio.NewReader(conn)) {
+					tls.Server(conn, config).Handshake()
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// ldapExtendedResponse encodes an LDAP StartTLS response with code
+func ldapExtendedResponse(code byte) []byte {
+	op := []byte{0x78, 0x07, 0x0a, 0x01, code, 0x04, 0x00, 0x04, 0x00}
+	body := append([]byte{0x02, 0x01, 0x01}, op...)
+	return append([]byte{0x30, byte(len(body))}, body...)
+}
+
+func TestDialTLS(t *testing.T) {
+	smtp := func(extensions string) func(net.Conn, *bufio.Reader) bool {
+		return func(conn net.Conn, reader *bufio.Reader) bool {
+			fmt.Fprintf(conn, "220 mail.example.com ESMTP\r\n")
+			if line, _ := readLine(reader); !strings.HasPrefix(line, "EHLO") {
+				return false
+			}
+			fmt.Fprintf(conn, "250-mail.example.com\r\n%s250 8BITMIME\r\n", extensions)
+			if line, _ := readLine(reader); line != "STARTTLS" {
+				return false
+			}
+			fmt.Fprintf(conn, "220 Ready to start TLS\r\n")
+			return true
+		}
+	}
+	tests := []struct {
+		name     string
+		protocol string
+		preamble func(conn net.Conn, reader *bufio.Reader) bool
+		wantErr  string
+	}{
+		{
+			name:     "direct",
+			protocol: models.TLSDirect,
+			preamble: func(net.Conn, *bufio.Reader) bool { return true },
+		},
+		{
+			name:     "smtp",
+			protocol: models.TLSSMTP,
+			preamble: smtp("250-starttls\r\n"),
+		},
+		{
+			name:     "smtp without STARTTLS",
+			protocol: models.TLSSMTP,
+			preamble: smtp("250-SIZE 10240000\r\n"),
+			wantErr:  "does not offer STARTTLS",
+		},
+		{
+			name:     "imap",
+			protocol: models.TLSIMAP,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				fmt.Fprintf(conn, "* OK IMAP4rev1 ready\r\n")
+				if line, _ := readLine(reader); line != "a1 STARTTLS" {
+					return false
+				}
+				fmt.Fprintf(conn, "* CAPABILITY IMAP4rev1\r\na1 OK Begin TLS negotiation now\r\n")
+				return true
+			},
+		},
+		{
+			name:     "imap refused",
+			protocol: models.TLSIMAP,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				fmt.Fprintf(conn, "* OK IMAP4rev1 ready\r\n")
+				readLine(reader)
+				fmt.Fprintf(conn, "a1 BAD STARTTLS not supported\r\n")
+				return false
+			},
+			wantErr: "a1 BAD",
+		},
+		{
+			name:     "pop3",
+			protocol: models.TLSPOP3,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				fmt.Fprintf(conn, "+OK POP3 ready\r\n")
+				if line, _ := readLine(reader); line != "STLS" {
+					return false
+				}
+				fmt.Fprintf(conn, "+OK Begin TLS\r\n")
+				return true
+			},
+		},
+		{
+			name:     "pop3 greeting",
+			protocol: models.TLSPOP3,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				fmt.Fprintf(conn, "-ERR go away\r\n")
+				return false
+			},
+			wantErr: "pop3 greeting",
+		},
+		{
+			name:     "ldap",
+			protocol: models.TLSLDAP,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				if tag, _, err := readBER(reader); err != nil || tag != 0x30 {
+					return false
+				}
+				conn.Write(ldapExtendedResponse(0))
+				return true
+			},
+		},
+		{
+			name:     "ldap refused",
+			protocol: models.TLSLDAP,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				readBER(reader)
+				conn.Write(ldapExtendedResponse(2))
+				return false
+			},
+			wantErr: "result code 2",
+		},
+		{
+			name:     "postgres",
+			protocol: models.TLSPostgres,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				request := make([]byte, 8)
+				if _, err := io.ReadFull(reader, request); err != nil {
+					return false
+				}
+				conn.Write([]byte{'S'})
+				return true
+			},
+		},
+		{
+			name:     "postgres without SSL",
+			protocol: models.TLSPostgres,
+			preamble: func(conn net.Conn, reader *bufio.Reader) bool {
+				io.ReadFull(reader, make([]byte, 8))
+				conn.Write([]byte{'N'})
+				return false
+			},
+			wantErr: "does not accept SSL",
+		},
+		{
+			name:     "unknown protocol",
+			protocol: "gopher",
+			preamble: func(net.Conn, *bufio.Reader) bool { return false },
+			wantErr:  "unsupported TLS protocol",
+		},
+	}
+
+	leaf := newTestCert(t, testCertOptions{name: "localhost", hosts: []string{"localhost"}}, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := newStartTLSServer(t, leaf, tt.preamble)
+			conn, err := dialTLS(addr, "localhost", tt.protocol, 5*time.Second)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("dialTLS() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dialTLS() error = %v", err)
+			}
+			defer conn.Close()
+			if certs := conn.ConnectionState().PeerCertificates; len(certs) != 1 || !certs[0].Equal(leaf.cert) {
+				t.Errorf("PeerCertificates = %d certificates, want the stand-in's", len(certs))
+			}
+		})
+	}
+}
+
+func TestTLSProtocol(t *testing.T) {
+	tests := []struct {
+		website  models.Website
+		want     string
+		wantPort string
+	}{
+		{models.Website{URL: "example.com:443"}, models.TLSDirect, "443"},
+		{models.Website{URL: "smtp://mail.example.com"}, models.TLSSMTP, "25"},
+		{models.Website{URL: "IMAP://mail.example.com"}, models.TLSIMAP, "143"},
+		{models.Website{URL: "https://example.com"}, models.TLSDirect, "443"},
+		{models.Website{URL: "db.example.com", TLSProtocol: "Postgres"}, models.TLSPostgres, "5432"},
+		{models.Website{URL: "smtp://mail.example.com", TLSProtocol: models.TLSPOP3}, models.TLSPOP3, "110"},
+	}
+	for _, tt := range tests {
+		got := TLSProtocol(tt.website)
+		if got != tt.want || TLSDefaultPort(got) != tt.wantPort {
+			t.Errorf("TLSProtocol(%+v) = %s on %s, want %s on %s", tt.website, got, TLSDefaultPort(got), tt.want, tt.wantPort)
+		}
+	}
+}
+
+func TestReadBER(t *testing.T) {
+	long := make([]byte, 300)
+	tests := []struct {
+		name    string
+		input   []byte
+		wantLen int
+		wantErr bool
+	}{
+		{"short form", []byte{0x04, 0x02, 'h', 'i'}, 2, false},
+		{"long form", append([]byte{0x04, 0x82, 0x01, 0x2c}, long...), 300, false},
+		{"indefinite length", []byte{0x30, 0x80}, 0, true},
+		{"truncated", []byte{0x04, 0x05, 'h'}, 0, true},
+	}
+	for _, tt := range tests {
+		tag, contents, err := readBER(bufio.NewReader(strings.NewReader(string(tt.input))))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: readBER() error = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && (tag != tt.input[0] || len(contents) != tt.wantLen) {
+			t.Errorf("%s: readBER() = tag %#x with %d bytes, want %#x with %d", tt.name, tag, len(contents), tt.input[0], tt.wantLen)
+		}
+	}
+}
@@ -1,320 +1,110 @@
 package services
 
 import (
-	"context"
 	"fmt"
-	"log"
 	"os"
-	"time"
+	"strings"
 
-	"github.com/joho/godotenv"
 	"github.com/prateeks007/PulseWatch/monitor/backend/models"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
-	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-type StorageService struct {
-	client       *mongo.Client
-	websitesColl *mongo.Collection
-	statusesColl *mongo.Collection
-	sslColl      *mongo.Collection
-	usersColl    *mongo.Collection
-	databaseName string
-	mongoURI     string
-}
-
-func NewStorageService() (*StorageService, error) {
-	_ = godotenv.Load()
-	uri := os.Getenv("MONGO_URI")
-	dbName := os.Getenv("MONGO_DB_NAME")
-	if dbName == "" {
-		dbName = "pulsewatch_db"
-	}
-	if uri == "" {
-		return nil, fmt.Errorf("MONGO_URI environment variable not set")
-	}
-	s := &StorageService{mongoURI: uri, databaseName: dbName}
-	if err := s.ConnectMongoDB(uri, dbName); err != nil {
-		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
-	}
-	log.Printf("✅ Storage service initialized successfully")
-	return s, nil
-}
-
-func (s *StorageService) ConnectMongoDB(uri, dbName string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-
-	clientOptions := options.Client().ApplyURI(uri)
-	client, err := mongo.Connect(ctx, clientOptions)
-	if err != nil {
-		return err
-	}
-	if err = client.Ping(ctx, readpref.Primary()); err != nil {
-		return err
-	}
-	s.client = client
-	db := client.Database(dbName)
-	s.websitesColl = db.Collection("websites")
-	s.statusesColl = db.Collection("statuses")
-	s.sslColl = db.Collection("ssl")
-	s.usersColl = db.Collection("users")
-
-	log.Println("Connected to Mongo!")
-	return nil
-}
-
-func (s *StorageService) GetWebsites() ([]models.Website, error) {
-	var sites []models.Website
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// Storage backends
+const (
+	StoreMongo = "mongo" // MongoDB, configured by MONGO_URI and MONGO_DB_NAME
+	StoreBolt  = "bolt"  // Embedded bbolt file, configured by BOLT_PATH
+)
 
-	cursor, err := s.websitesColl.Find(ctx, bson.M{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to find websites: %w", err)
-	}
-	defer func() {
-		if err := cursor.Close(ctx); err != nil {
-			log.Printf("⚠️ Failed to close cursor: %v", err)
+// Store persists websites, their check results and everything derived
+// from them. MongoStore and BoltStore implement it.
+type Store interface {
+	// Websites
+	GetWebsites() ([]models.Website, error)
+	GetWebsitesByUser(userID string) ([]models.Website, error)
+	GetWebsiteByUser(id, userID string) (*models.Website, error)
+	GetWebsiteByPushToken(token string) (*models.Website, error)
+	SaveWebsite(website models.Website) error
+	DeleteWebsite(id string) error
+	DeleteWebsiteByUser(id, userID string) error
+
+	// Statuses
+	SaveStatus(status models.WebsiteStatus) error
+	GetWebsiteStatuses(websiteID string) ([]models.WebsiteStatus, error)
+	GetLatestStatus(websiteID string) (*models.WebsiteStatus, error)
+	DeleteStatusesBefore(before int64) (int64, error)
+	DeleteOrphanedStatuses() (int64, error)
+
+	// SSL and domains
+	SaveSSL(info models.SSLInfo) error
+	GetSSL(websiteID string) *models.SSLInfo
+	RecordCertificate(record models.CertificateRecord) (bool, error)
+	GetCertificateHistory(websiteID string) ([]models.CertificateRecord, error)
+	SaveDomain(info models.DomainInfo) error
+	GetDomain(domain string) (*models.DomainInfo, error)
+
+	// Users
+	GetUser(userID string) (*models.User, error)
+	SaveUser(user models.User) error
+
+	// Monitor state
+	GetMonitorState(websiteID string) (*models.MonitorState, error)
+	UpdateMonitorState(websiteID string, update func(*models.MonitorState)) (*models.MonitorState, error)
+
+	// Incidents
+	SaveIncident(incident models.Incident) error
+	GetOpenIncident(websiteID string) (*models.Incident, error)
+	RecordIncidentFailure(websiteID string, statusCode int, reason string, at int64) error
+	AddIncidentEvent(websiteID string, event models.IncidentEvent) error
+	ResolveIncident(websiteID string, at int64, message string) (*models.Incident, error)
+	GetIncidentByUser(id, userID string) (*models.Incident, error)
+	GetIncidentsByUser(userID, status string, limit int64) ([]models.Incident, error)
+	GetIncidentsByWebsite(websiteID string, limit int64) ([]models.Incident, error)
+	GetIncidentsSince(userID, websiteID string, since int64) ([]models.Incident, error)
+	AcknowledgeIncident(id, by string, at int64) (*models.Incident, bool, error)
+	AddIncidentNote(id string, note models.IncidentNote) error
+	SetIncidentRootCause(id, rootCause string, at int64) error
+	GetEscalatingIncidents() ([]models.Incident, error)
+	ClaimEscalation(incident models.Incident, step int, at int64, event *models.IncidentEvent) (bool, error)
+
+	// Maintenance windows
+	SaveMaintenanceWindow(window models.MaintenanceWindow) error
+	GetMaintenanceWindowsByUser(userID string) ([]models.MaintenanceWindow, error)
+	DeleteMaintenanceWindowByUser(id, userID string) error
+
+	// Close releases the connection or file
+	Close() error
+}
+
+// NewStore opens the backend named by STORAGE_BACKEND. When it is unset,
+// MongoDB is used and MONGO_URI is required; the embedded store is only
+// used when asked for, so a missing MONGO_URI never silently starts an
+// empty local database.
+func NewStore() (Store, error) {
+	backend := strings.ToLower(os.Getenv("STORAGE_BACKEND"))
+	if backend == "" {
+		if os.Getenv("MONGO_URI") == "" {
+			return nil, fmt.Errorf("MONGO_URI environment variable not set (set STORAGE_BACKEND=%s to use the embedded store)", StoreBolt)
 		}
-	}()
-
-	if err := cursor.All(ctx, &sites); err != nil {
-		return nil, fmt.Errorf("failed to decode websites: %w", err)
+		backend = StoreMongo
 	}
-	return sites, nil
-}
-
-// GetWebsitesByUser returns websites filtered by user_id
-func (s *StorageService) GetWebsitesByUser(userID string) ([]models.Website, error) {
-	var sites []models.Website
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
 
-	cursor, err := s.websitesColl.Find(ctx, bson.M{"user_id": userID})
-	if err != nil {
-		return nil, fmt.Errorf("failed to find websites for user %s: %w", userID, err)
-	}
-	defer func() {
-		if err := cursor.Close(ctx); err != nil {
-			log.Printf("⚠️ Failed to close cursor: %v", err)
+	switch backend {
+	case StoreMongo:
+		dbName := os.Getenv("MONGO_DB_NAME")
+		if dbName == "" {
+			dbName = "pulsewatch_db"
 		}
-	}()
-
-	if err := cursor.All(ctx, &sites); err != nil {
-		return nil, fmt.Errorf("failed to decode websites for user %s: %w", userID, err)
-	}
-	return sites, nil
-}
-
-func (s *StorageService) SaveWebsite(website models.Website) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err := s.websitesColl.UpdateOne(
-		ctx,
-		bson.M{"_id": website.ID},
-		bson.M{"$set": website},
-		options.Update().SetUpsert(true),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to save website %s: %w", website.Name, err)
-	}
-	return nil
-}
-
-func (s *StorageService) SaveStatus(status models.WebsiteStatus) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	doc := bson.M{
-		"website_id":       status.WebsiteID,
-		"is_up":            status.IsUp,
-		"status_code":      status.StatusCode,
-		"response_time_ms": status.ResponseTime,
-		"checked_at":       status.CheckedAt,
-		"checked_at_date":  time.Unix(status.CheckedAt, 0),
-	}
-	_, err := s.statusesColl.InsertOne(ctx, doc)
-	if err != nil {
-		return fmt.Errorf("failed to save status for website %s: %w", status.WebsiteID, err)
-	}
-	return nil
-}
-
-func (s *StorageService) GetWebsiteStatuses(websiteID string) ([]models.WebsiteStatus, error) {
-	var statuses []models.WebsiteStatus
-	ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
-	defer cancel()
-
-	cursor, err := s.statusesColl.Find(
-		ctx,
-		bson.M{"website_id": websiteID},
-		options.Find().SetSort(bson.D{{Key: "checked_at", Value: -1}}).SetLimit(500),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find statuses for website %s: %w", websiteID, err)
-	}
-	defer func() {
-		if err := cursor.Close(ctx); err != nil {
-			log.Printf("⚠️ Failed to close cursor: %v", err)
+		return NewMongoStore(os.Getenv("MONGO_URI"), dbName)
+	case StoreBolt:
+		if os.Getenv("MONGO_URI") != "" || os.Getenv("MONGO_DB_NAME") != "" {
+			fmt.Println("⚠️ STORAGE_BACKEND is bolt, ignoring MONGO_URI and MONGO_DB_NAME")
 		}
-	}()
-
-	if err := cursor.All(ctx, &statuses); err != nil {
-		return nil, fmt.Errorf("failed to decode statuses for website %s: %w", websiteID, err)
-	}
-	return statuses, nil
-}
-
-// --- SSL ---
-
-func (s *StorageService) SaveSSL(info models.SSLInfo) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	_, err := s.sslColl.UpdateOne(
-		ctx,
-		bson.M{"website_id": info.WebsiteID},
-		bson.M{"$set": info},
-		options.Update().SetUpsert(true),
-	)
-	if err != nil {
-		log.Printf("SaveSSL UpdateOne error: %v", err)
-	}
-	return err
-}
-
-func (s *StorageService) GetSSL(websiteID string) *models.SSLInfo {
-	var out models.SSLInfo
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	err := s.sslColl.FindOne(ctx, bson.M{"website_id": websiteID}).Decode(&out)
-	if err != nil {
-		if err != mongo.ErrNoDocuments {
-			log.Printf("GetSSL error: %v", err)
-		}
-		return nil
-	}
-	return &out
-}
-
-func (s *StorageService) DeleteWebsite(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if _, err := s.websitesColl.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
-		log.Printf("DeleteWebsite websites delete error: %v", err)
-	}
-	if _, err := s.statusesColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
-		log.Printf("DeleteWebsite statuses delete error: %v", err)
-	}
-	if _, err := s.sslColl.DeleteOne(ctx, bson.M{"website_id": id}); err != nil {
-		log.Printf("DeleteWebsite ssl delete error: %v", err)
-	}
-	return nil
-}
-
-// DeleteWebsiteByUser deletes a website only if it belongs to the user
-func (s *StorageService) DeleteWebsiteByUser(id, userID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Delete only if the website belongs to the user
-	result, err := s.websitesColl.DeleteOne(ctx, bson.M{"_id": id, "user_id": userID})
-	if err != nil {
-		log.Printf("DeleteWebsiteByUser websites delete error: %v", err)
-		return err
-	}
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("website not found or access denied")
-	}
-
-	// Clean up related data
-	if _, err := s.statusesColl.DeleteMany(ctx, bson.M{"website_id": id}); err != nil {
-		log.Printf("DeleteWebsiteByUser statuses delete error: %v", err)
-	}
-	if _, err := s.sslColl.DeleteOne(ctx, bson.M{"website_id": id}); err != nil {
-		log.Printf("DeleteWebsiteByUser ssl delete error: %v", err)
-	}
-	return nil
-}
-
-// GetWebsiteByUser returns a website only if it belongs to the user
-func (s *StorageService) GetWebsiteByUser(id, userID string) (*models.Website, error) {
-	var website models.Website
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	err := s.websitesColl.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&website)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("website not found or access denied")
+		path := os.Getenv("BOLT_PATH")
+		if path == "" {
+			path = "pulsewatch.db"
 		}
-		return nil, fmt.Errorf("failed to find website: %w", err)
-	}
-	return &website, nil
-}
-
-// GetStatusesCollection returns the statuses collection for cleanup service
-func (s *StorageService) GetStatusesCollection() *mongo.Collection {
-	return s.statusesColl
-}
-
-// GetWebsitesCollection returns the websites collection for cleanup service
-func (s *StorageService) GetWebsitesCollection() *mongo.Collection {
-	return s.websitesColl
-}
-
-// --- User Management ---
-
-// GetUser returns user settings by user ID
-func (s *StorageService) GetUser(userID string) (*models.User, error) {
-	var user models.User
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	err := s.usersColl.FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, nil // User not found, return nil
-		}
-		return nil, fmt.Errorf("failed to find user: %w", err)
-	}
-	return &user, nil
-}
-
-// SaveUser saves or updates user settings
-func (s *StorageService) SaveUser(user models.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	user.UpdatedAt = time.Now().Unix()
-	if user.CreatedAt == 0 {
-		user.CreatedAt = user.UpdatedAt
-	}
-
-	_, err := s.usersColl.UpdateOne(
-		ctx,
-		bson.M{"_id": user.ID},
-		bson.M{"$set": user},
-		options.Update().SetUpsert(true),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to save user %s: %w", user.ID, err)
-	}
-	return nil
-}
-
-// GetUserDiscordWebhook returns the Discord webhook URL for a user
-func (s *StorageService) GetUserDiscordWebhook(userID string) (string, error) {
-	user, err := s.GetUser(userID)
-	if err != nil {
-		return "", err
-	}
-	if user == nil {
-		return "", nil // User not found, no webhook
+		fmt.Printf("💾 Using the embedded store at %s\n", path)
+		return NewBoltStore(path)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q (use %q or %q)", backend, StoreMongo, StoreBolt)
 	}
-	return user.DiscordWebhookURL, nil
 }
@@ -0,0 +1,542 @@
+package services
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// testStore runs the Store suite against the stores open returns. Each
+// test gets a new, empty store.
+func testStore(t *testing.T, open func(t *testing.T) Store) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store Store)
+	}{
+		{"websites", testStoreWebsites},
+		{"users, SSL and domains", testStoreSettings},
+		{"statuses", testStoreStatuses},
+		{"delete statuses before", testStoreDeleteStatusesBefore},
+		{"delete orphaned statuses", testStoreDeleteOrphanedStatuses},
+		{"delete website data", testStoreDeleteWebsiteData},
+		{"certificate history", testStoreCertificateHistory},
+		{"monitor state", testStoreMonitorState},
+		{"concurrent monitor state updates", testStoreConcurrentMonitorState},
+		{"incidents", testStoreIncidents},
+		{"incident queries", testStoreIncidentQueries},
+		{"escalation", testStoreEscalation},
+		{"maintenance windows", testStoreMaintenanceWindows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, open(t))
+		})
+	}
+}
+
+func testStoreWebsites(t *testing.T, store Store) {
+	websites := []models.Website{
+		{ID: "a", UserID: "u1", Name: "A", URL: "https://a.example.com"},
+		{ID: "b", UserID: "u2", Name: "B", URL: "https://b.example.com"},
+		{ID: "c", UserID: "u1", Name: "C", Type: models.MonitorPush, PushToken: "token"},
+	}
+	for _, website := range websites {
+		if err := store.SaveWebsite(website); err != nil {
+			t.Fatalf("SaveWebsite(%s) error = %v", website.ID, err)
+		}
+	}
+
+	all, err := store.GetWebsites()
+	if err != nil || len(all) != 3 {
+		t.Fatalf("GetWebsites() = %d websites, %v, want 3", len(all), err)
+	}
+	mine, err := store.GetWebsitesByUser("u1")
+	if err != nil || len(mine) != 2 {
+		t.Errorf("GetWebsitesByUser(u1) = %d websites, %v, want 2", len(mine), err)
+	}
+	if got, err := store.GetWebsiteByUser("a", "u1"); err != nil || !reflect.DeepEqual(*got, websites[0]) {
+		t.Errorf("GetWebsiteByUser(a, u1) = %+v, %v, want %+v", got, err, websites[0])
+	}
+	if _, err := store.GetWebsiteByUser("a", "u2"); err == nil {
+		t.Errorf("GetWebsiteByUser(a, u2) returned another user's website")
+	}
+	if got, err := store.GetWebsiteByPushToken("token"); err != nil || got.ID != "c" {
+		t.Errorf("GetWebsiteByPushToken() = %+v, %v, want c", got, err)
+	}
+	if _, err := store.GetWebsiteByPushToken("other"); err == nil {
+		t.Errorf("GetWebsiteByPushToken(other) found a website")
+	}
+
+	websites[0].Name = "Renamed"
+	if err := store.SaveWebsite(websites[0]); err != nil {
+		t.Fatalf("SaveWebsite() error = %v", err)
+	}
+	if got, _ := store.GetWebsiteByUser("a", "u1"); got == nil || got.Name != "Renamed" {
+		t.Errorf("GetWebsiteByUser() after an update = %+v, want it renamed", got)
+	}
+
+	if err := store.DeleteWebsiteByUser("a", "u2"); err == nil {
+		t.Errorf("DeleteWebsiteByUser(a, u2) deleted another user's website")
+	}
+	if err := store.DeleteWebsiteByUser("a", "u1"); err != nil {
+		t.Errorf("DeleteWebsiteByUser(a, u1) error = %v", err)
+	}
+	if err := store.DeleteWebsite("b"); err != nil {
+		t.Errorf("DeleteWebsite(b) error = %v", err)
+	}
+	if all, _ := store.GetWebsites(); len(all) != 1 || all[0].ID != "c" {
+		t.Errorf("GetWebsites() after deleting = %+v, want only c", all)
+	}
+}
+
+func testStoreSettings(t *testing.T, store Store) {
+	if user, err := store.GetUser("u1"); err != nil || user != nil {
+		t.Errorf("GetUser() before saving = %+v, %v, want nil", user, err)
+	}
+	if err := store.SaveUser(models.User{ID: "u1", SSLExpiryThresholds: []int{30, 7}}); err != nil {
+		t.Fatalf("SaveUser() error = %v", err)
+	}
+	user, err := store.GetUser("u1")
+	if err != nil || user == nil || !reflect.DeepEqual(user.SSLExpiryThresholds, []int{30, 7}) || user.CreatedAt == 0 {
+		t.Errorf("GetUser() = %+v, %v, want the saved settings", user, err)
+	}
+
+	if info := store.GetSSL("w1"); info != nil {
+		t.Errorf("GetSSL() before saving = %+v, want nil", info)
+	}
+	ssl := models.SSLInfo{WebsiteID: "w1", Issuer: "Test CA", ValidTo: 1800000000, DaysLeft: 30, OCSPStatus: models.OCSPGood}
+	if err := store.SaveSSL(ssl); err != nil {
+		t.Fatalf("SaveSSL() error = %v", err)
+	}
+	if info := store.GetSSL("w1"); info == nil || !reflect.DeepEqual(*info, ssl) {
+		t.Errorf("GetSSL() = %+v, want %+v", info, ssl)
+	}
+
+	if info, err := store.GetDomain("example.com"); err != nil || info != nil {
+		t.Errorf("GetDomain() before saving = %+v, %v, want nil", info, err)
+	}
+	domain := models.DomainInfo{Domain: "example.com", Registrar: "Example Registrar", ExpiresAt: 1800000000, DaysLeft: 90}
+	if err := store.SaveDomain(domain); err != nil {
+		t.Fatalf("SaveDomain() error = %v", err)
+	}
+	if info, err := store.GetDomain("example.com"); err != nil || info == nil || !reflect.DeepEqual(*info, domain) {
+		t.Errorf("GetDomain() = %+v, %v, want %+v", info, err, domain)
+	}
+}
+
+func testStoreStatuses(t *testing.T, store Store) {
+	if latest, err := store.GetLatestStatus("w1"); err != nil || latest != nil {
+		t.Errorf("GetLatestStatus() without statuses = %+v, %v, want nil", latest, err)
+	}
+
+	// Saved out of order, and twice in the same second
+	for _, status := range []models.WebsiteStatus{
+		{WebsiteID: "w1", CheckedAt: 200, StatusCode: 200},
+		{WebsiteID: "w1", CheckedAt: 100, StatusCode: 500},
+		{WebsiteID: "w1", CheckedAt: 300, StatusCode: 301},
+		{WebsiteID: "w1", CheckedAt: 300, StatusCode: 302},
+		{WebsiteID: "w2", CheckedAt: 400, StatusCode: 404},
+	} {
+		if err := store.SaveStatus(status); err != nil {
+			t.Fatalf("SaveStatus() error = %v", err)
+		}
+	}
+
+	statuses, err := store.GetWebsiteStatuses("w1")
+	if err != nil {
+		t.Fatalf("GetWebsiteStatuses() error = %v", err)
+	}
+	var codes []int
+	for _, status := range statuses {
+		if status.ID.IsZero() {
+			t.Errorf("status checked at %d has no ID", status.CheckedAt)
+		}
+		codes = append(codes, status.StatusCode)
+	}
+	if want := []int{302, 301, 200, 500}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("GetWebsiteStatuses() codes = %v, want newest first %v", codes, want)
+	}
+	if latest, err := store.GetLatestStatus("w1"); err != nil || latest == nil || latest.StatusCode != 302 {
+		t.Errorf("GetLatestStatus() = %+v, %v, want the last saved of the newest second", latest, err)
+	}
+}
+
+func testStoreDeleteStatusesBefore(t *testing.T, store Store) {
+	for _, status := range []models.WebsiteStatus{
+		{WebsiteID: "w1", CheckedAt: 100},
+		{WebsiteID: "w1", CheckedAt: 150},
+		{WebsiteID: "w1", CheckedAt: 200},
+		{WebsiteID: "w2", CheckedAt: 50},
+		{WebsiteID: "w2", CheckedAt: 300},
+	} {
+		if err := store.SaveStatus(status); err != nil {
+			t.Fatalf("SaveStatus() error = %v", err)
+		}
+	}
+
+	if deleted, err := store.DeleteStatusesBefore(200); err != nil || deleted != 3 {
+		t.Errorf("DeleteStatusesBefore(200) = %d, %v, want 3", deleted, err)
+	}
+	for websiteID, want := range map[string]int64{"w1": 200, "w2": 300} {
+		statuses, _ := store.GetWebsiteStatuses(websiteID)
+		if len(statuses) != 1 || statuses[0].CheckedAt != want {
+			t.Errorf("statuses of %s = %+v, want only the one at %d", websiteID, statuses, want)
+		}
+	}
+	if deleted, err := store.DeleteStatusesBefore(200); err != nil || deleted != 0 {
+		t.Errorf("DeleteStatusesBefore(200) again = %d, %v, want 0", deleted, err)
+	}
+}
+
+func testStoreDeleteOrphanedStatuses(t *testing.T, store Store) {
+	if err := store.SaveWebsite(models.Website{ID: "kept"}); err != nil {
+		t.Fatalf("SaveWebsite() error = %v", err)
+	}
+	for _, status := range []models.WebsiteStatus{
+		{WebsiteID: "kept", CheckedAt: 100},
+		{WebsiteID: "gone", CheckedAt: 100},
+		{WebsiteID: "gone", CheckedAt: 200},
+	} {
+		if err := store.SaveStatus(status); err != nil {
+			t.Fatalf("SaveStatus() error = %v", err)
+		}
+	}
+
+	if deleted, err := store.DeleteOrphanedStatuses(); err != nil || deleted != 2 {
+		t.Errorf("DeleteOrphanedStatuses() = %d, %v, want 2", deleted, err)
+	}
+	if statuses, _ := store.GetWebsiteStatuses("kept"); len(statuses) != 1 {
+		t.Errorf("statuses of an existing website = %d, want 1 kept", len(statuses))
+	}
+	if statuses, _ := store.GetWebsiteStatuses("gone"); len(statuses) != 0 {
+		t.Errorf("statuses of a deleted website = %d, want 0", len(statuses))
+	}
+}
+
+func testStoreDeleteWebsiteData(t *testing.T, store Store) {
+	// "w1" and "w10" share a prefix, only w1's data may go
+	for _, id := range []string{"w1", "w10"} {
+		if err := store.SaveWebsite(models.Website{ID: id, UserID: "u1"}); err != nil {
+			t.Fatalf("SaveWebsite() error = %v", err)
+		}
+		if err := store.SaveStatus(models.WebsiteStatus{WebsiteID: id, CheckedAt: 100}); err != nil {
+			t.Fatalf("SaveStatus() error = %v", err)
+		}
+		if err := store.SaveSSL(models.SSLInfo{WebsiteID: id, Issuer: "Test CA"}); err != nil {
+			t.Fatalf("SaveSSL() error = %v", err)
+		}
+		for _, fingerprint := range []string{"aa", "bb", "cc"} {
+			record := models.CertificateRecord{ID: id + ":" + fingerprint, WebsiteID: id, Fingerprint: fingerprint}
+			if _, err := store.RecordCertificate(record); err != nil {
+				t.Fatalf("RecordCertificate() error = %v", err)
+			}
+		}
+		if _, err := store.UpdateMonitorState(id, func(state *models.MonitorState) { state.IsUp = true }); err != nil {
+			t.Fatalf("UpdateMonitorState() error = %v", err)
+		}
+		if err := store.SaveIncident(models.Incident{ID: "incident-" + id, WebsiteID: id, UserID: "u1", Status: models.IncidentOpen}); err != nil {
+			t.Fatalf("SaveIncident() error = %v", err)
+		}
+	}
+
+	if err := store.DeleteWebsiteByUser("w1", "u1"); err != nil {
+		t.Fatalf("DeleteWebsiteByUser() error = %v", err)
+	}
+
+	for id, want := range map[string]bool{"w1": false, "w10": true} {
+		statuses, _ := store.GetWebsiteStatuses(id)
+		history, _ := store.GetCertificateHistory(id)
+		state, _ := store.GetMonitorState(id)
+		incidents, _ := store.GetIncidentsByWebsite(id, 0)
+		got := []bool{len(statuses) == 1, len(history) == 3, state != nil, len(incidents) == 1, store.GetSSL(id) != nil}
+		if wantAll := []bool{want, want, want, want, want}; !reflect.DeepEqual(got, wantAll) {
+			t.Errorf("%s has statuses, certificates, state, incident and SSL %v, want %v", id, got, wantAll)
+		}
+	}
+}
+
+func testStoreCertificateHistory(t *testing.T, store Store) {
+	first := models.CertificateRecord{ID: "w1:aa", WebsiteID: "w1", Fingerprint: "aa", Issuer: "Old CA", FirstSeen: 100, LastSeen: 100}
+	second := models.CertificateRecord{ID: "w1:bb", WebsiteID: "w1", Fingerprint: "bb", Issuer: "New CA", FirstSeen: 200, LastSeen: 200}
+	for _, record := range []models.CertificateRecord{first, second} {
+		if isNew, err := store.RecordCertificate(record); err != nil || !isNew {
+			t.Fatalf("RecordCertificate(%s) = %v, %v, want new", record.ID, isNew, err)
+		}
+	}
+
+	// Seen again: only last_seen changes
+	again := first
+	again.Issuer = "Changed"
+	again.FirstSeen = 300
+	again.LastSeen = 300
+	if isNew, err := store.RecordCertificate(again); err != nil || isNew {
+		t.Fatalf("RecordCertificate() again = %v, %v, want not new", isNew, err)
+	}
+
+	history, err := store.GetCertificateHistory("w1")
+	if err != nil {
+		t.Fatalf("GetCertificateHistory() error = %v", err)
+	}
+	first.LastSeen = 300
+	if want := []models.CertificateRecord{second, first}; !reflect.DeepEqual(history, want) {
+		t.Errorf("GetCertificateHistory() = %+v, want %+v", history, want)
+	}
+	if history, err := store.GetCertificateHistory("w2"); err != nil || history == nil || len(history) != 0 {
+		t.Errorf("GetCertificateHistory() without certificates = %#v, %v, want empty", history, err)
+	}
+}
+
+func testStoreMonitorState(t *testing.T, store Store) {
+	if state, err := store.GetMonitorState("w1"); err != nil || state != nil {
+		t.Fatalf("GetMonitorState() before any check = %+v, %v, want nil", state, err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		state, err := store.UpdateMonitorState("w1", func(state *models.MonitorState) {
+			state.ConsecutiveFailures++
+		})
+		if err != nil {
+			t.Fatalf("UpdateMonitorState() error = %v", err)
+		}
+		if state.WebsiteID != "w1" || state.Version != int64(i) || state.ConsecutiveFailures != i {
+			t.Errorf("update %d = %+v, want version and failures %d", i, state, i)
+		}
+	}
+
+	stored, err := store.GetMonitorState("w1")
+	if err != nil || stored == nil || stored.Version != 3 || stored.ConsecutiveFailures != 3 {
+		t.Errorf("GetMonitorState() = %+v, %v, want version 3", stored, err)
+	}
+}
+
+func testStoreConcurrentMonitorState(t *testing.T, store Store) {
+	// Lost updates would leave fewer failures than updates
+	const updates = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, updates)
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.UpdateMonitorState("w1", func(state *models.MonitorState) {
+				state.ConsecutiveFailures++
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("UpdateMonitorState() error = %v", err)
+		}
+	}
+	state, err := store.GetMonitorState("w1")
+	if err != nil || state.ConsecutiveFailures != updates || state.Version != updates {
+		t.Errorf("GetMonitorState() = %+v, %v, want each of the %d updates counted once", state, err, updates)
+	}
+}
+
+func testStoreIncidents(t *testing.T, store Store) {
+	incident := models.Incident{
+		ID:        "i1",
+		WebsiteID: "w1",
+		UserID:    "u1",
+		Status:    models.IncidentOpen,
+		StartedAt: 1000,
+		Timeline:  []models.IncidentEvent{{Type: models.EventOpened, At: 1000, Message: "HTTP 503"}},
+	}
+	if err := store.SaveIncident(incident); err != nil {
+		t.Fatalf("SaveIncident() error = %v", err)
+	}
+	if err := store.SaveIncident(incident); err == nil {
+		t.Errorf("SaveIncident() replaced an existing incident")
+	}
+
+	failures := []struct {
+		code   int
+		reason string
+	}{{503, "HTTP 503"}, {503, "HTTP 503"}, {0, "connection refused"}}
+	for i, failure := range failures {
+		if err := store.RecordIncidentFailure("w1", failure.code, failure.reason, 1010+int64(i)); err != nil {
+			t.Fatalf("RecordIncidentFailure() error = %v", err)
+		}
+	}
+	if err := store.AddIncidentEvent("w1", models.IncidentEvent{Type: models.EventFlapping, At: 1020, Message: "Started flapping"}); err != nil {
+		t.Fatalf("AddIncidentEvent() error = %v", err)
+	}
+
+	acked, changed, err := store.AcknowledgeIncident("i1", "u1", 1030)
+	if err != nil || !changed || acked.AcknowledgedBy != "u1" {
+		t.Fatalf("AcknowledgeIncident() = %+v, %v, %v, want acknowledged", acked, changed, err)
+	}
+	if _, changed, err := store.AcknowledgeIncident("i1", "someone else", 1031); err != nil || changed {
+		t.Errorf("AcknowledgeIncident() again = %v, %v, want unchanged", changed, err)
+	}
+	if _, _, err := store.AcknowledgeIncident("missing", "u1", 1031); err == nil {
+		t.Errorf("AcknowledgeIncident() of a missing incident succeeded")
+	}
+	if err := store.AddIncidentNote("i1", models.IncidentNote{At: 1040, Author: "u1", Text: "Restarting the pod"}); err != nil {
+		t.Fatalf("AddIncidentNote() error = %v", err)
+	}
+	if err := store.SetIncidentRootCause("i1", "Bad deploy", 1050); err != nil {
+		t.Fatalf("SetIncidentRootCause() error = %v", err)
+	}
+
+	resolved, err := store.ResolveIncident("w1", 1060, "Website is back up")
+	if err != nil || resolved == nil {
+		t.Fatalf("ResolveIncident() = %+v, %v", resolved, err)
+	}
+	if resolved.Status != models.IncidentResolved || resolved.ResolvedAt != 1060 || resolved.DurationSeconds != 60 {
+		t.Errorf("ResolveIncident() = %+v, want resolved after 60s", resolved)
+	}
+	if again, err := store.ResolveIncident("w1", 1070, "Website is back up"); err != nil || again != nil {
+		t.Errorf("ResolveIncident() without an open incident = %+v, %v, want nil", again, err)
+	}
+
+	got, err := store.GetIncidentByUser("i1", "u1")
+	if err != nil {
+		t.Fatalf("GetIncidentByUser() error = %v", err)
+	}
+	if got.FailedChecks != 3 || !reflect.DeepEqual(got.StatusCodes, []int{503}) || got.LastError != "connection refused" ||
+		!reflect.DeepEqual(got.Errors, []string{"HTTP 503", "connection refused"}) || got.RootCause != "Bad deploy" || len(got.Notes) != 1 {
+		t.Errorf("GetIncidentByUser() = %+v, want the recorded failures, note and root cause", got)
+	}
+	var events []string
+	for _, event := range got.Timeline {
+		events = append(events, event.Type)
+	}
+	want := []string{
+		models.EventOpened, models.EventFailure, models.EventFailure, models.EventFlapping,
+		models.EventAcknowledged, models.EventNote, models.EventRootCause, models.EventResolved,
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("timeline = %v, want %v", events, want)
+	}
+	if _, err := store.GetIncidentByUser("i1", "u2"); err == nil {
+		t.Errorf("GetIncidentByUser(i1, u2) returned another user's incident")
+	}
+}
+
+func testStoreIncidentQueries(t *testing.T, store Store) {
+	for _, incident := range []models.Incident{
+		{ID: "old", WebsiteID: "w1", UserID: "u1", Status: models.IncidentResolved, StartedAt: 100},
+		{ID: "open", WebsiteID: "w1", UserID: "u1", Status: models.IncidentOpen, StartedAt: 300},
+		{ID: "other-site", WebsiteID: "w2", UserID: "u1", Status: models.IncidentResolved, StartedAt: 200},
+		{ID: "other-user", WebsiteID: "w3", UserID: "u2", Status: models.IncidentOpen, StartedAt: 400},
+	} {
+		if err := store.SaveIncident(incident); err != nil {
+			t.Fatalf("SaveIncident() error = %v", err)
+		}
+	}
+
+	ids := func(incidents []models.Incident, err error) []string {
+		if err != nil {
+			t.Fatalf("query error = %v", err)
+		}
+		var ids []string
+		for _, incident := range incidents {
+			ids = append(ids, incident.ID)
+		}
+		return ids
+	}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"by user, newest first", ids(store.GetIncidentsByUser("u1", "", 0)), []string{"open", "other-site", "old"}},
+		{"by user and status", ids(store.GetIncidentsByUser("u1", models.IncidentResolved, 0)), []string{"other-site", "old"}},
+		{"by user with a limit", ids(store.GetIncidentsByUser("u1", "", 1)), []string{"open"}},
+		{"by website", ids(store.GetIncidentsByWebsite("w1", 0)), []string{"open", "old"}},
+		{"since", ids(store.GetIncidentsSince("u1", "", 200)), []string{"open", "other-site"}},
+		{"since for a website", ids(store.GetIncidentsSince("u1", "w2", 200)), []string{"other-site"}},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	open, err := store.GetOpenIncident("w1")
+	if err != nil || open == nil || open.ID != "open" {
+		t.Errorf("GetOpenIncident(w1) = %+v, %v, want open", open, err)
+	}
+	if open, err := store.GetOpenIncident("w2"); err != nil || open != nil {
+		t.Errorf("GetOpenIncident(w2) = %+v, %v, want nil", open, err)
+	}
+}
+
+func testStoreEscalation(t *testing.T, store Store) {
+	for _, incident := range []models.Incident{
+		{ID: "escalating", WebsiteID: "w1", Status: models.IncidentOpen, EscalationPolicyID: "p1"},
+		{ID: "no-policy", WebsiteID: "w2", Status: models.IncidentOpen},
+		{ID: "acknowledged", WebsiteID: "w3", Status: models.IncidentOpen, EscalationPolicyID: "p1", AcknowledgedAt: 100},
+		{ID: "resolved", WebsiteID: "w4", Status: models.IncidentResolved, EscalationPolicyID: "p1"},
+	} {
+		if err := store.SaveIncident(incident); err != nil {
+			t.Fatalf("SaveIncident() error = %v", err)
+		}
+	}
+
+	escalating, err := store.GetEscalatingIncidents()
+	if err != nil || len(escalating) != 1 || escalating[0].ID != "escalating" {
+		t.Fatalf("GetEscalatingIncidents() = %+v, %v, want only escalating", escalating, err)
+	}
+
+	event := &models.IncidentEvent{Type: models.EventEscalated, At: 500, Message: "Step 1"}
+	if claimed, err := store.ClaimEscalation(escalating[0], 1, 500, event); err != nil || !claimed {
+		t.Fatalf("ClaimEscalation() = %v, %v, want claimed", claimed, err)
+	}
+	// A second scheduler holding the same read loses
+	if claimed, err := store.ClaimEscalation(escalating[0], 1, 501, event); err != nil || claimed {
+		t.Errorf("ClaimEscalation() with a stale incident = %v, %v, want not claimed", claimed, err)
+	}
+
+	incidents, _ := store.GetIncidentsByWebsite("w1", 0)
+	if len(incidents) != 1 || incidents[0].EscalationStep != 1 || incidents[0].LastNotifiedAt != 500 || len(incidents[0].Timeline) != 1 {
+		t.Errorf("incident after claiming = %+v, want step 1 notified at 500", incidents)
+	}
+}
+
+func testStoreMaintenanceWindows(t *testing.T, store Store) {
+	windows := []models.MaintenanceWindow{
+		{ID: "m2", UserID: "u1", Name: "Later", StartsAt: 100, EndsAt: 200, CreatedAt: 20},
+		{ID: "m1", UserID: "u1", Name: "Earlier", Schedule: "0 2 * * 2", DurationMinutes: 30, CreatedAt: 10},
+		{ID: "m3", UserID: "u2", Name: "Someone else's", StartsAt: 100, EndsAt: 200, CreatedAt: 5},
+	}
+	for _, window := range windows {
+		if err := store.SaveMaintenanceWindow(window); err != nil {
+			t.Fatalf("SaveMaintenanceWindow(%s) error = %v", window.ID, err)
+		}
+	}
+
+	got, err := store.GetMaintenanceWindowsByUser("u1")
+	if err != nil {
+		t.Fatalf("GetMaintenanceWindowsByUser() error = %v", err)
+	}
+	if want := []models.MaintenanceWindow{windows[1], windows[0]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetMaintenanceWindowsByUser() = %+v, want oldest first %+v", got, want)
+	}
+
+	taken := windows[2]
+	taken.UserID = "u1"
+	if err := store.SaveMaintenanceWindow(taken); err == nil {
+		t.Errorf("SaveMaintenanceWindow() replaced another user's window")
+	}
+	if err := store.DeleteMaintenanceWindowByUser("m3", "u1"); err == nil {
+		t.Errorf("DeleteMaintenanceWindowByUser() deleted another user's window")
+	}
+	if err := store.DeleteMaintenanceWindowByUser("m1", "u1"); err != nil {
+		t.Fatalf("DeleteMaintenanceWindowByUser() error = %v", err)
+	}
+	if got, _ := store.GetMaintenanceWindowsByUser("u1"); len(got) != 1 || got[0].ID != "m2" {
+		t.Errorf("GetMaintenanceWindowsByUser() after deleting = %+v, want only m2", got)
+	}
+	if got, err := store.GetMaintenanceWindowsByUser("nobody"); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("GetMaintenanceWindowsByUser(nobody) = %#v, %v, want empty", got, err)
+	}
+}
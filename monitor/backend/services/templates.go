@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+	"github.com/prateeks007/PulseWatch/monitor/backend/utils"
+)
+
+// AlertTemplateData is what a channel's title and body templates are
+// executed against, e.g. {{.Website.Name}}, {{.Status.StatusCode}},
+// {{.Timings.TTFBMs}} or {{.Incident.ID}}
+type AlertTemplateData struct {
+	Event       string                    // One of the models.Alert* types
+	IsUp        bool                      // Whether the alert announces a recovery
+	Reminder    bool                      // Whether the alert repeats an unacknowledged down alert
+	Website     models.Website            // Website the alert is about
+	Status      models.WebsiteStatus      // Check result that triggered the alert
+	Timings     *models.Timings           // HTTP timing breakdown of Status, if any
+	Incident    *models.Incident          // Incident the alert belongs to, if any
+	State       *models.MonitorState      // Monitor state after the check, if known
+	SSL         *models.SSLInfo           // Certificate check behind an SSL alert, if any
+	Certificate *models.CertificateRecord // History record of a replaced certificate, if any
+	Domain      *models.DomainInfo        // Registration behind a domain expiry alert, if any
+
+	Title           string    // Default title
+	Message         string    // Default body
+	Summary         string    // Recovery summary, e.g. "Was down for 14m, last error: ..."
+	Downtime        string    // Formatted downtime, e.g. "14m"
+	DowntimeSeconds int64     // Downtime in seconds
+	LastError       string    // Last failure reason of the incident
+	AckURL          string    // One-click acknowledge link, if any
+	Time            time.Time // When the alert was raised
+}
+
+// TemplateData returns the data alert templates are executed against
+func (a Alert) TemplateData() AlertTemplateData {
+	website := a.Website
+	if website.Type == models.MonitorPush {
+		// Never leak the ping token into alert channels
+		website.URL = ""
+		website.PushToken = ""
+	}
+	data := AlertTemplateData{
+		Event:       a.Type,
+		IsUp:        a.IsUp(),
+		Reminder:    a.Reminder,
+		Website:     website,
+		Status:      a.Status,
+		Timings:     a.Status.Timings,
+		Incident:    a.Incident,
+		State:       a.State,
+		SSL:         a.SSL,
+		Certificate: a.Certificate,
+		Domain:      a.Domain,
+		Title:       a.defaultTitle(),
+		Message:     a.defaultMessage(),
+		Summary:     a.Summary(),
+		LastError:   a.LastError(),
+		AckURL:      a.AckURL,
+		Time:        a.Time,
+	}
+	if a.Incident != nil {
+		data.Downtime = FormatDuration(a.Downtime())
+		data.DowntimeSeconds = int64(a.Downtime().Seconds())
+	}
+	return data
+}
+
+// RenderTemplates returns the alert with the channel's title and body
+// templates applied. Without templates the alert is returned unchanged.
+func (a Alert) RenderTemplates(channel models.NotificationChannel) (Alert, error) {
+	if channel.TitleTemplate == "" && channel.BodyTemplate == "" {
+		return a, nil
+	}
+	title, body, err := RenderAlertTemplates(channel.TitleTemplate, channel.BodyTemplate, a)
+	if err != nil {
+		return a, err
+	}
+	a.title = title
+	a.body = body
+	return a, nil
+}
+
+// RenderAlertTemplates executes title and body templates against an
+// alert. An empty template renders as "" so the default is kept.
+func RenderAlertTemplates(titleTemplate, bodyTemplate string, alert Alert) (title, body string, err error) {
+	data := alert.TemplateData()
+	render := func(name, text string) (string, error) {
+		if text == "" {
+			return "", nil
+		}
+		tmpl, err := utils.ParseAlertTemplate(name, text)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", name, err)
+		}
+		var out bytes.Buffer
+		if err := tmpl.Execute(&out, data); err != nil {
+			return "", fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		return strings.TrimSpace(out.String()), nil
+	}
+
+	if title, err = render("title_template", titleTemplate); err != nil {
+		return "", "", err
+	}
+	// Titles are a single line everywhere they are shown
+	title = strings.Join(strings.Fields(title), " ")
+	if body, err = render("body_template", bodyTemplate); err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+// SampleAlert returns an alert with realistic sample data, used to test
+// channels and preview templates
+func SampleAlert(alertType string) Alert {
+	now := time.Now()
+	startedAt := now.Add(-14 * time.Minute)
+	website := models.Website{
+		ID:       "sample",
+		Name:     "PulseWatch Sample",
+		URL:      "https://example.com",
+		Interval: 60,
+		Type:     models.MonitorHTTP,
+	}
+	incident := &models.Incident{
+		ID:           "sample-incident",
+		WebsiteID:    website.ID,
+		WebsiteName:  website.Name,
+		Status:       models.IncidentOpen,
+		StartedAt:    startedAt.Unix(),
+		FailedChecks: 14,
+		StatusCodes:  []int{503},
+		Errors:       []string{"HTTP 503", "connection refused"},
+		LastError:    "connection refused",
+		Timeline: []models.IncidentEvent{
+			{Type: models.EventOpened, At: startedAt.Unix(), Message: "HTTP 503"},
+			{Type: models.EventFailure, At: startedAt.Add(5 * time.Minute).Unix(), Message: "connection refused"},
+		},
+	}
+	alert := Alert{
+		Website: website,
+		Type:    alertType,
+		Time:    now,
+	}
+
+	if alertType == models.AlertUp {
+		incident.Status = models.IncidentResolved
+		incident.ResolvedAt = now.Unix()
+		incident.DurationSeconds = now.Unix() - incident.StartedAt
+		alert.Status = models.WebsiteStatus{
+			WebsiteID:    website.ID,
+			IsUp:         true,
+			StatusCode:   200,
+			ResponseTime: 123,
+			CheckedAt:    now.Unix(),
+			Attempts:     1,
+			Timings:      &models.Timings{DNSMs: 4, ConnectMs: 12, TLSMs: 31, TTFBMs: 70, TransferMs: 6},
+		}
+	} else {
+		incident.Timeline = incident.Timeline[:1]
+		incident.Errors = incident.Errors[:1]
+		incident.LastError = "HTTP 503"
+		incident.FailedChecks = 1
+		incident.StartedAt = now.Unix()
+		alert.Status = models.WebsiteStatus{
+			WebsiteID:    website.ID,
+			IsUp:         false,
+			StatusCode:   503,
+			ResponseTime: 87,
+			CheckedAt:    now.Unix(),
+			Attempts:     2,
+			Timings:      &models.Timings{DNSMs: 4, ConnectMs: 12, TLSMs: 31, TTFBMs: 38, TransferMs: 2},
+		}
+		alert.AckURL = "https://example.com" + AckPath(incident.ID) + "?expires=0&sig=sample"
+	}
+	alert.Incident = incident
+	return alert
+}
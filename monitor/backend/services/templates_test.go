@@ -0,0 +1,141 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestRenderAlertTemplates(t *testing.T) {
+	alert := Alert{
+		Website:  models.Website{ID: "w1", Name: "Example", URL: "https://example.com"},
+		Type:     models.AlertUp,
+		Status:   models.WebsiteStatus{StatusCode: 200, Timings: &models.Timings{TTFBMs: 70}},
+		Time:     time.Unix(1700000000, 0),
+		Incident: &models.Incident{ID: "i1", Status: models.IncidentResolved, StartedAt: 1699999160, DurationSeconds: 840, LastError: "connection refused"},
+	}
+	tests := []struct {
+		name      string
+		title     string
+		body      string
+		wantTitle string
+		wantBody  string
+		wantErr   string
+	}{
+		{
+			name:      "fields",
+			title:     "{{.Website.Name}} {{.Event}}",
+			body:      "{{.Summary}}\nTTFB {{.Timings.TTFBMs}}ms, incident {{.Incident.ID}}",
+			wantTitle: "Example up",
+			wantBody:  "Was down for 14m, last error: connection refused\nTTFB 70ms, incident i1",
+		},
+		{
+			name:      "helpers",
+			title:     "{{upper .Website.Name}} since {{formatTime .Incident.StartedAt \"15:04 MST\"}}",
+			body:      "{{default .Status.Error \"no error\"}} / {{lower .Downtime}}",
+			wantTitle: "EXAMPLE since 21:59 UTC",
+			wantBody:  "no error / 14m",
+		},
+		{
+			name:      "titles are one line",
+			title:     "{{.Website.Name}}\n  is\tback  ",
+			wantTitle: "Example is back",
+		},
+		{
+			name:     "empty templates keep the defaults",
+			body:     "  {{.DowntimeSeconds}}\n",
+			wantBody: "840",
+		},
+		{
+			name:    "parse errors",
+			title:   "{{.Website.Name",
+			wantErr: "invalid title_template",
+		},
+		{
+			name:    "execution errors",
+			body:    "{{.Website.Nope}}",
+			wantErr: "failed to render body_template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, body, err := RenderAlertTemplates(tt.title, tt.body, alert)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("RenderAlertTemplates() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderAlertTemplates() error = %v", err)
+			}
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("RenderAlertTemplates() = %q, %q, want %q, %q", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestTemplateDataHidesPushToken(t *testing.T) {
+	alert := Alert{
+		Website: models.Website{Name: "Nightly backup", Type: models.MonitorPush, URL: "/api/push/secret-token", PushToken: "secret-token"},
+		Type:    models.AlertDown,
+	}
+	title, body, err := RenderAlertTemplates("{{.Website.URL}}", "{{.Website.PushToken}}{{.Message}}", alert)
+	if err != nil {
+		t.Fatalf("RenderAlertTemplates() error = %v", err)
+	}
+	if strings.Contains(title+body, "secret-token") {
+		t.Errorf("templates leak the ping token: %q, %q", title, body)
+	}
+}
+
+func TestSampleAlertRenders(t *testing.T) {
+	for _, alertType := range []string{models.AlertDown, models.AlertUp} {
+		alert := SampleAlert(alertType)
+		title, body, err := RenderAlertTemplates("{{.Title}}", "{{.Message}}", alert)
+		if err != nil {
+			t.Fatalf("%s: RenderAlertTemplates() error = %v", alertType, err)
+		}
+		if title != alert.Title() || body != alert.Message() {
+			t.Errorf("%s: sample renders %q, %q, want the defaults %q, %q", alertType, title, body, alert.Title(), alert.Message())
+		}
+	}
+}
+
+func TestNotifyRendersTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		wantTitle string
+		wantErr   bool
+	}{
+		{"rendered", "{{.Website.Name}} needs you", "Example needs you", false},
+		{"broken templates still deliver the default", "{{.Nope}}", "❌ Example is OFFLINE", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRecordingServer(t, http.StatusOK)
+			channel := models.NotificationChannel{Type: models.ChannelWebhook, Name: "hook", URL: server.URL, TitleTemplate: tt.title}
+			err := NewNotificationService(nil).Notify([]models.NotificationChannel{channel}, testAlert())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Notify() error = %v, want error %v", err, tt.wantErr)
+			}
+
+			var payload struct {
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal([]byte((<-requests).body), &payload); err != nil {
+				t.Fatalf("invalid payload: %v", err)
+			}
+			if payload.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", payload.Title, tt.wantTitle)
+			}
+		})
+	}
+}
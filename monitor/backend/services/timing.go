@@ -0,0 +1,123 @@
+package services
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// requestTimer collects the phases of an HTTP request through httptrace.
+// Phases repeated across redirects are summed.
+type requestTimer struct {
+	mu sync.Mutex
+
+	start     time.Time
+	dnsStart  time.Time
+	connStart time.Time
+	tlsStart  time.Time
+	wrote     time.Time
+	firstByte time.Time
+	end       time.Time
+
+	dns      time.Duration
+	connect  time.Duration
+	tls      time.Duration
+	ttfb     time.Duration
+	transfer time.Duration
+}
+
+func newRequestTimer() *requestTimer {
+	return &requestTimer{start: time.Now()}
+}
+
+// trace returns the hooks that feed the timer
+func (t *requestTimer) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dns += time.Since(t.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Dual-stack dialing may start several connects; time from the first
+			if t.connStart.IsZero() {
+				t.connStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil && !t.connStart.IsZero() {
+				t.connect += time.Since(t.connStart)
+				t.connStart = time.Time{}
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tls += time.Since(t.tlsStart)
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.wrote = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.firstByte = time.Now()
+			if !t.wrote.IsZero() {
+				t.ttfb += t.firstByte.Sub(t.wrote)
+			}
+		},
+	}
+}
+
+// bodyDone marks the end of reading the final response body
+func (t *requestTimer) bodyDone() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.end = time.Now()
+	if !t.firstByte.IsZero() {
+		t.transfer = t.end.Sub(t.firstByte)
+	}
+}
+
+// total returns the time from the start of the request until the body was
+// read, or until now if it hasn't been
+func (t *requestTimer) total() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.end.IsZero() {
+		return time.Since(t.start)
+	}
+	return t.end.Sub(t.start)
+}
+
+// timings returns the collected phases in milliseconds
+func (t *requestTimer) timings() *models.Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &models.Timings{
+		DNSMs:      t.dns.Milliseconds(),
+		ConnectMs:  t.connect.Milliseconds(),
+		TLSMs:      t.tls.Milliseconds(),
+		TTFBMs:     t.ttfb.Milliseconds(),
+		TransferMs: t.transfer.Milliseconds(),
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestCheckTimings(t *testing.T) {
+	const delay = 40 * time.Millisecond
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.Write([]byte("start"))
+		w.(http.Flusher).Flush()
+		time.Sleep(delay)
+		w.Write([]byte("end"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		wantTTFB time.Duration // Minimum time to first byte, summed over redirects
+	}{
+		{"single request", "/", delay},
+		{"redirected", "/old", 2 * delay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := NewMonitorService(NewSSLService())
+			monitor.client = server.Client()
+			status, err := monitor.CheckWebsite(models.Website{ID: "w1", URL: server.URL + tt.path})
+			if err != nil || !status.IsUp {
+				t.Fatalf("CheckWebsite() = up %v, %v", status.IsUp, err)
+			}
+
+			timings := status.Timings
+			if timings == nil {
+				t.Fatal("Timings = nil")
+			}
+			if timings.TTFBMs < tt.wantTTFB.Milliseconds() {
+				t.Errorf("TTFBMs = %d, want at least %d", timings.TTFBMs, tt.wantTTFB.Milliseconds())
+			}
+			if timings.TransferMs < delay.Milliseconds() {
+				t.Errorf("TransferMs = %d, want at least %d", timings.TransferMs, delay.Milliseconds())
+			}
+			sum := timings.DNSMs + timings.ConnectMs + timings.TLSMs + timings.TTFBMs + timings.TransferMs
+			if sum > status.ResponseTime {
+				t.Errorf("phases add up to %dms, more than the %dms response time", sum, status.ResponseTime)
+			}
+		})
+	}
+}
+
+func TestCheckTimingsOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	status, err := NewMonitorService(NewSSLService()).CheckWebsite(models.Website{ID: "w1", URL: url})
+	if err == nil || status.IsUp {
+		t.Fatalf("CheckWebsite() of a closed server = up %v, %v", status.IsUp, err)
+	}
+	if status.Timings == nil || status.Timings.TTFBMs != 0 || status.Timings.TransferMs != 0 {
+		t.Errorf("Timings = %+v, want only the phases reached", status.Timings)
+	}
+}
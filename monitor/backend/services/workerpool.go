@@ -0,0 +1,200 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+const (
+	defaultPoolWorkers      = 10
+	defaultPoolPerHostLimit = 2
+	defaultPoolQueueSize    = 1000
+)
+
+// WorkerPoolConfig controls the size and limits of a WorkerPool
+type WorkerPoolConfig struct {
+	Workers      int // Number of concurrent checks
+	PerHostLimit int // Max concurrent checks against the same host
+	QueueSize    int // Max checks waiting for a worker
+}
+
+// WorkerPoolConfigFromEnv reads CHECK_WORKERS, CHECK_PER_HOST_LIMIT and
+// CHECK_QUEUE_SIZE, falling back to defaults when unset or invalid
+func WorkerPoolConfigFromEnv() WorkerPoolConfig {
+	return WorkerPoolConfig{
+		Workers:      envInt("CHECK_WORKERS", defaultPoolWorkers),
+		PerHostLimit: envInt("CHECK_PER_HOST_LIMIT", defaultPoolPerHostLimit),
+		QueueSize:    envInt("CHECK_QUEUE_SIZE", defaultPoolQueueSize),
+	}
+}
+
+// WorkerPoolStats is a snapshot of the pool's queue and throughput
+type WorkerPoolStats struct {
+	Workers      int    `json:"workers"`
+	PerHostLimit int    `json:"per_host_limit"`
+	Queued       int    `json:"queued"`       // Waiting for a worker
+	HostWaiting  int    `json:"host_waiting"` // Waiting for a per-host slot
+	InFlight     int    `json:"in_flight"`    // Currently being checked
+	Completed    uint64 `json:"completed"`    // Checks finished since start
+	Skipped      uint64 `json:"skipped"`      // Submitted while already pending
+	Dropped      uint64 `json:"dropped"`      // Rejected because the queue was full
+	MaxQueued    int    `json:"max_queued"`   // Highest queue depth seen
+	AvgRunMs     int64  `json:"avg_run_ms"`   // Average check duration
+}
+
+// WorkerPool runs website checks on a bounded number of workers
+type WorkerPool struct {
+	cfg   WorkerPoolConfig
+	queue chan models.Website
+	run   func(models.Website)
+
+	mu          sync.Mutex
+	pending     map[string]bool             // Website IDs queued or in flight
+	hostActive  map[string]int              // In-flight checks per host
+	hostWaiting map[string][]models.Website // Checks parked until a host slot frees up
+	stats       WorkerPoolStats
+	totalRun    time.Duration
+}
+
+// NewWorkerPool creates a pool that calls run for every submitted website
+func NewWorkerPool(cfg WorkerPoolConfig, run func(models.Website)) *WorkerPool {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultPoolWorkers
+	}
+	if cfg.PerHostLimit <= 0 {
+		cfg.PerHostLimit = defaultPoolPerHostLimit
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultPoolQueueSize
+	}
+	return &WorkerPool{
+		cfg:         cfg,
+		queue:       make(chan models.Website, cfg.QueueSize),
+		run:         run,
+		pending:     make(map[string]bool),
+		hostActive:  make(map[string]int),
+		hostWaiting: make(map[string][]models.Website),
+	}
+}
+
+// Start launches the workers
+func (p *WorkerPool) Start() {
+	for i := 0; i < p.cfg.Workers; i++ {
+		go p.worker()
+	}
+	fmt.Printf("👷 Started %d check workers (per-host limit %d)\n", p.cfg.Workers, p.cfg.PerHostLimit)
+}
+
+// Submit queues a website check. It returns false if the website already
+// has a check pending or the queue is full.
+func (p *WorkerPool) Submit(website models.Website) bool {
+	p.mu.Lock()
+	if p.pending[website.ID] {
+		p.stats.Skipped++
+		p.mu.Unlock()
+		return false
+	}
+	p.pending[website.ID] = true
+	p.mu.Unlock()
+
+	select {
+	case p.queue <- website:
+		p.mu.Lock()
+		if depth := len(p.queue); depth > p.stats.MaxQueued {
+			p.stats.MaxQueued = depth
+		}
+		p.mu.Unlock()
+		return true
+	default:
+		p.mu.Lock()
+		delete(p.pending, website.ID)
+		p.stats.Dropped++
+		p.mu.Unlock()
+		fmt.Printf("⚠️ Check queue full, dropped check for %s\n", website.Name)
+		return false
+	}
+}
+
+// Stats returns a snapshot of the pool's metrics
+func (p *WorkerPool) Stats() WorkerPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Workers = p.cfg.Workers
+	stats.PerHostLimit = p.cfg.PerHostLimit
+	stats.Queued = len(p.queue)
+	for _, waiting := range p.hostWaiting {
+		stats.HostWaiting += len(waiting)
+	}
+	if stats.Completed > 0 {
+		stats.AvgRunMs = (p.totalRun / time.Duration(stats.Completed)).Milliseconds()
+	}
+	return stats
+}
+
+func (p *WorkerPool) worker() {
+	for website := range p.queue {
+		host := TargetHost(website)
+
+		// Park the check if its host is already at the limit; it is run
+		// by one of the running checks for that host once it finishes
+		p.mu.Lock()
+		if p.hostActive[host] >= p.cfg.PerHostLimit {
+			p.hostWaiting[host] = append(p.hostWaiting[host], website)
+			p.mu.Unlock()
+			continue
+		}
+		p.hostActive[host]++
+		p.stats.InFlight++
+		p.mu.Unlock()
+
+		for next := &website; next != nil; {
+			next = p.runCheck(host, *next)
+		}
+	}
+}
+
+// runCheck runs a check holding one of its host's slots. The slot is then
+// handed to the next check parked for the host, which is returned, or
+// freed if there is none.
+func (p *WorkerPool) runCheck(host string, website models.Website) *models.Website {
+	start := time.Now()
+	p.run(website)
+	elapsed := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.Completed++
+	p.totalRun += elapsed
+	delete(p.pending, website.ID)
+
+	if waiting := p.hostWaiting[host]; len(waiting) > 0 {
+		next := waiting[0]
+		if len(waiting) == 1 {
+			delete(p.hostWaiting, host)
+		} else {
+			p.hostWaiting[host] = waiting[1:]
+		}
+		return &next
+	}
+	p.hostActive[host]--
+	if p.hostActive[host] == 0 {
+		delete(p.hostActive, host)
+	}
+	p.stats.InFlight--
+	return nil
+}
+
+// envInt reads a positive integer from the environment
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+// blockingRun records how many checks run against each host at once and
+// blocks every check until released
+type blockingRun struct {
+	release chan struct{}
+
+	mu      sync.Mutex
+	active  map[string]int
+	maxSeen map[string]int
+	ran     map[string]int // Checks run per website ID
+}
+
+func newBlockingRun() *blockingRun {
+	return &blockingRun{
+		release: make(chan struct{}),
+		active:  make(map[string]int),
+		maxSeen: make(map[string]int),
+		ran:     make(map[string]int),
+	}
+}
+
+func (b *blockingRun) run(website models.Website) {
+	host := TargetHost(website)
+	b.mu.Lock()
+	b.active[host]++
+	if b.active[host] > b.maxSeen[host] {
+		b.maxSeen[host] = b.active[host]
+	}
+	b.mu.Unlock()
+
+	<-b.release
+
+	b.mu.Lock()
+	b.active[host]--
+	b.ran[website.ID]++
+	b.mu.Unlock()
+}
+
+// waitFor polls until done reports true, failing the test after a while
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWorkerPoolPerHostLimit(t *testing.T) {
+	blocking := newBlockingRun()
+	pool := NewWorkerPool(WorkerPoolConfig{Workers: 8, PerHostLimit: 2, QueueSize: 100}, blocking.run)
+	pool.Start()
+
+	const perHost = 10
+	hosts := []string{"a.example.com", "b.example.com"}
+	for i := 0; i < perHost; i++ {
+		for _, host := range hosts {
+			website := models.Website{ID: fmt.Sprintf("%s-%d", host, i), URL: fmt.Sprintf("https://%s/%d", host, i)}
+			if !pool.Submit(website) {
+				t.Fatalf("Submit(%s) was rejected", website.ID)
+			}
+		}
+	}
+
+	// Both hosts fill their slots, the rest of their checks are parked
+	waitFor(t, "every host slot to fill", func() bool {
+		stats := pool.Stats()
+		return stats.InFlight == 4 && stats.Queued == 0
+	})
+	if stats := pool.Stats(); stats.HostWaiting != len(hosts)*perHost-4 {
+		t.Errorf("HostWaiting = %d, want %d", stats.HostWaiting, len(hosts)*perHost-4)
+	}
+
+	close(blocking.release)
+	waitFor(t, "every check to complete", func() bool {
+		return pool.Stats().Completed == uint64(len(hosts)*perHost)
+	})
+
+	blocking.mu.Lock()
+	defer blocking.mu.Unlock()
+	for _, host := range hosts {
+		if blocking.maxSeen[host] > 2 {
+			t.Errorf("%d checks ran against %s at once, want at most 2", blocking.maxSeen[host], host)
+		}
+	}
+	if len(blocking.ran) != len(hosts)*perHost {
+		t.Errorf("%d websites were checked, want %d", len(blocking.ran), len(hosts)*perHost)
+	}
+	for id, n := range blocking.ran {
+		if n != 1 {
+			t.Errorf("%s was checked %d times", id, n)
+		}
+	}
+	if stats := pool.Stats(); stats.InFlight != 0 || stats.HostWaiting != 0 || stats.Queued != 0 {
+		t.Errorf("Stats() = %+v after every check completed", stats)
+	}
+}
+
+func TestWorkerPoolSkipsPending(t *testing.T) {
+	blocking := newBlockingRun()
+	pool := NewWorkerPool(WorkerPoolConfig{Workers: 2, PerHostLimit: 1, QueueSize: 10}, blocking.run)
+	pool.Start()
+
+	running := models.Website{ID: "a", URL: "https://example.com/a"}
+	parked := models.Website{ID: "b", URL: "https://example.com/b"}
+	pool.Submit(running)
+	pool.Submit(parked)
+	waitFor(t, "the second check to be parked", func() bool { return pool.Stats().HostWaiting == 1 })
+
+	if pool.Submit(running) || pool.Submit(parked) {
+		t.Errorf("Submit() accepted a website with a check pending")
+	}
+	if stats := pool.Stats(); stats.Skipped != 2 {
+		t.Errorf("Skipped = %d, want 2", stats.Skipped)
+	}
+
+	close(blocking.release)
+	waitFor(t, "both checks to complete", func() bool { return pool.Stats().Completed == 2 })
+	if !pool.Submit(running) {
+		t.Errorf("Submit() rejected a website whose check completed")
+	}
+	waitFor(t, "the resubmitted check to complete", func() bool { return pool.Stats().Completed == 3 })
+}
+
+func TestWorkerPoolDropsWhenFull(t *testing.T) {
+	// Not started, so nothing leaves the queue
+	pool := NewWorkerPool(WorkerPoolConfig{Workers: 1, QueueSize: 2}, func(models.Website) {})
+	for i, want := range []bool{true, true, false, false} {
+		website := models.Website{ID: fmt.Sprint(i), URL: "https://example.com"}
+		if got := pool.Submit(website); got != want {
+			t.Errorf("Submit(#%d) = %v, want %v", i, got, want)
+		}
+	}
+
+	stats := pool.Stats()
+	if stats.Queued != 2 || stats.MaxQueued != 2 || stats.Dropped != 2 || stats.Skipped != 0 {
+		t.Errorf("Stats() = %+v, want 2 queued and 2 dropped", stats)
+	}
+	// A dropped check isn't left pending
+	pool.Start()
+	waitFor(t, "the queue to drain", func() bool { return pool.Stats().Completed == 2 })
+	if !pool.Submit(models.Website{ID: "2", URL: "https://example.com"}) {
+		t.Errorf("Submit() rejected a website whose check was dropped")
+	}
+}
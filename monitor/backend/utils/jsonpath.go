@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is one step of a JSONPath: an object key or an array index
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the subset of JSONPath PulseWatch supports:
+// $.key, $.key.nested, $.list[0], $["key with spaces"] and combinations
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", path)
+	}
+
+	var segments []jsonPathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSONPath %q has an empty key", path)
+			}
+			segments = append(segments, jsonPathSegment{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed [", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, jsonPathSegment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("JSONPath %q has an invalid index %q", path, inner)
+			}
+			segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+		default:
+			return nil, fmt.Errorf("JSONPath %q is invalid near %q", path, rest)
+		}
+	}
+	return segments, nil
+}
+
+// ValidateJSONPath reports whether path is a supported JSONPath
+func ValidateJSONPath(path string) error {
+	_, err := parseJSONPath(path)
+	return err
+}
+
+// EvalJSONPath returns the value at path in a document decoded with
+// encoding/json. Negative indexes count from the end of an array.
+func EvalJSONPath(doc interface{}, path string) (interface{}, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, segment := range segments {
+		if segment.isIndex {
+			list, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: not an array", path)
+			}
+			index := segment.index
+			if index < 0 {
+				index += len(list)
+			}
+			if index < 0 || index >= len(list) {
+				return nil, fmt.Errorf("%s: index %d out of range", path, segment.index)
+			}
+			current = list[index]
+			continue
+		}
+
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: not an object", path)
+		}
+		value, ok := object[segment.key]
+		if !ok {
+			return nil, fmt.Errorf("%s: key %q not found", path, segment.key)
+		}
+		current = value
+	}
+	return current, nil
+}
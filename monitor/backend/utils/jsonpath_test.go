@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{
+		"status": "ok",
+		"db": {"healthy": true, "latency_ms": 12},
+		"nodes": [{"name": "a"}, {"name": "b"}],
+		"key with spaces": 1
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr bool
+	}{
+		{path: "$", want: doc},
+		{path: "$.status", want: "ok"},
+		{path: "$.db.healthy", want: true},
+		{path: "$.db.latency_ms", want: float64(12)},
+		{path: "$.nodes[1].name", want: "b"},
+		{path: "$.nodes[-1].name", want: "b"},
+		{path: `$["key with spaces"]`, want: float64(1)},
+		{path: "$['db']['healthy']", want: true},
+		{path: "$.missing", wantErr: true},
+		{path: "$.nodes[2]", wantErr: true},
+		{path: "$.nodes[-3]", wantErr: true},
+		{path: "$.status[0]", wantErr: true},
+		{path: "$.nodes.name", wantErr: true},
+		{path: "status", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := EvalJSONPath(doc, tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("EvalJSONPath(%q) error = %v, want error %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EvalJSONPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestValidateJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"$", false},
+		{"$.a.b[0]", false},
+		{`$["a"][0]`, false},
+		{" $.a ", false},
+		{"", true},
+		{"a.b", true},
+		{"$.", true},
+		{"$..a", true},
+		{"$.a[0", true},
+		{"$.a[x]", true},
+		{"$a", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateJSONPath(tt.path); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateJSONPath(%q) = %v, want error %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
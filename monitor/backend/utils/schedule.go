@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// rruleDays maps RRULE weekdays to cron day-of-week numbers
+var rruleDays = map[string]string{"SU": "0", "MO": "1", "TU": "2", "WE": "3", "TH": "4", "FR": "5", "SA": "6"}
+
+// ParseMaintenanceSchedule parses the start schedule of a recurring
+// maintenance window in the given IANA time zone (default UTC). The
+// schedule is either a standard 5-field cron expression ("0 22 * * 2")
+// or an RRULE ("FREQ=WEEKLY;BYDAY=TU;BYHOUR=22;BYMINUTE=0"). RRULEs
+// support FREQ=DAILY, WEEKLY (with BYDAY) and MONTHLY (with BYMONTHDAY),
+// plus BYHOUR and BYMINUTE.
+func ParseMaintenanceSchedule(schedule, timezone string) (cron.Schedule, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", timezone)
+	}
+
+	spec := strings.TrimSpace(schedule)
+	if strings.HasPrefix(strings.ToUpper(spec), "RRULE:") || strings.Contains(strings.ToUpper(spec), "FREQ=") {
+		var err error
+		if spec, err = rruleToCron(spec); err != nil {
+			return nil, err
+		}
+	} else if strings.HasPrefix(spec, "@") || strings.HasPrefix(strings.ToUpper(spec), "CRON_TZ=") || strings.HasPrefix(strings.ToUpper(spec), "TZ=") {
+		return nil, fmt.Errorf("schedule must be a 5-field cron expression or an RRULE")
+	}
+	return cron.ParseStandard("CRON_TZ=" + timezone + " " + spec)
+}
+
+// rruleToCron converts the supported subset of RRULE to a cron expression
+func rruleToCron(rule string) (string, error) {
+	rule = strings.TrimSpace(rule)
+	if len(rule) >= 6 && strings.EqualFold(rule[:6], "RRULE:") {
+		rule = rule[6:]
+	}
+
+	parts := make(map[string]string)
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return "", fmt.Errorf("invalid RRULE part %q", part)
+		}
+		parts[strings.ToUpper(key)] = strings.ToUpper(value)
+	}
+
+	numbers := func(key string, min, max int, fallback string) (string, error) {
+		value, ok := parts[key]
+		if !ok {
+			return fallback, nil
+		}
+		for _, n := range strings.Split(value, ",") {
+			if v, err := strconv.Atoi(n); err != nil || v < min || v > max {
+				return "", fmt.Errorf("%s must be numbers between %d and %d", key, min, max)
+			}
+		}
+		return value, nil
+	}
+
+	for key, value := range parts {
+		switch key {
+		case "FREQ", "BYDAY", "BYMONTHDAY", "BYHOUR", "BYMINUTE":
+		case "INTERVAL":
+			if value != "1" {
+				return "", fmt.Errorf("RRULE INTERVAL other than 1 is not supported")
+			}
+		default:
+			return "", fmt.Errorf("RRULE %s is not supported", key)
+		}
+	}
+
+	minute, err := numbers("BYMINUTE", 0, 59, "0")
+	if err != nil {
+		return "", err
+	}
+	hour, err := numbers("BYHOUR", 0, 23, "0")
+	if err != nil {
+		return "", err
+	}
+	dayOfMonth, dayOfWeek := "*", "*"
+
+	switch parts["FREQ"] {
+	case "DAILY":
+	case "WEEKLY":
+		days, ok := parts["BYDAY"]
+		if !ok {
+			return "", fmt.Errorf("weekly RRULEs need BYDAY")
+		}
+		var cronDays []string
+		for _, day := range strings.Split(days, ",") {
+			cronDay, ok := rruleDays[day]
+			if !ok {
+				return "", fmt.Errorf("invalid BYDAY value %q", day)
+			}
+			cronDays = append(cronDays, cronDay)
+		}
+		dayOfWeek = strings.Join(cronDays, ",")
+	case "MONTHLY":
+		if _, ok := parts["BYMONTHDAY"]; !ok {
+			return "", fmt.Errorf("monthly RRULEs need BYMONTHDAY")
+		}
+		if dayOfMonth, err = numbers("BYMONTHDAY", 1, 31, ""); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("RRULE FREQ must be DAILY, WEEKLY or MONTHLY")
+	}
+	if parts["FREQ"] != "WEEKLY" {
+		if _, ok := parts["BYDAY"]; ok {
+			return "", fmt.Errorf("BYDAY is only supported with FREQ=WEEKLY")
+		}
+	}
+	if parts["FREQ"] != "MONTHLY" {
+		if _, ok := parts["BYMONTHDAY"]; ok {
+			return "", fmt.Errorf("BYMONTHDAY is only supported with FREQ=MONTHLY")
+		}
+	}
+
+	return strings.Join([]string{minute, hour, dayOfMonth, "*", dayOfWeek}, " "), nil
+}
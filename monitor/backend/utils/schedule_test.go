@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRRULEToCron(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY", want: "0 0 * * *"},
+		{rule: "RRULE:FREQ=DAILY;BYHOUR=3;BYMINUTE=30", want: "30 3 * * *"},
+		{rule: "freq=weekly;byday=tu;byhour=22", want: "0 22 * * 2"},
+		{rule: "FREQ=WEEKLY;BYDAY=SA,SU;BYHOUR=1,13;BYMINUTE=15", want: "15 1,13 * * 6,0"},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=1,15;BYHOUR=2", want: "0 2 1,15 * *"},
+		{rule: "FREQ=DAILY;INTERVAL=1;", want: "0 0 * * *"},
+		{rule: "FREQ=DAILY;INTERVAL=2", wantErr: true},
+		{rule: "FREQ=HOURLY", wantErr: true},
+		{rule: "FREQ=WEEKLY", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rule: "FREQ=MONTHLY", wantErr: true},
+		{rule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rule: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=MO;BYMONTHDAY=1", wantErr: true},
+		{rule: "FREQ=DAILY;BYHOUR=24", wantErr: true},
+		{rule: "FREQ=DAILY;BYMINUTE=x", wantErr: true},
+		{rule: "FREQ=DAILY;COUNT=3", wantErr: true},
+		{rule: "FREQ=DAILY;BYHOUR", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := rruleToCron(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("rruleToCron(%q) error = %v, want error %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("rruleToCron(%q) = %q, want %q", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestParseMaintenanceSchedule(t *testing.T) {
+	from := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC) // A Monday
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone data: %v", err)
+	}
+
+	tests := []struct {
+		schedule string
+		timezone string
+		want     time.Time // Next start after from
+		wantErr  bool
+	}{
+		{schedule: "0 22 * * 2", want: time.Date(2024, 3, 5, 22, 0, 0, 0, time.UTC)},
+		{schedule: "FREQ=WEEKLY;BYDAY=TU;BYHOUR=22", want: time.Date(2024, 3, 5, 22, 0, 0, 0, time.UTC)},
+		{schedule: "FREQ=DAILY;BYHOUR=3", timezone: "America/New_York", want: time.Date(2024, 3, 5, 3, 0, 0, 0, newYork)},
+		{schedule: "0 22 * * 2", timezone: "Mars/Olympus_Mons", wantErr: true},
+		{schedule: "@daily", wantErr: true},
+		{schedule: "TZ=UTC 0 22 * * 2", wantErr: true},
+		{schedule: "not a schedule", wantErr: true},
+		{schedule: "FREQ=YEARLY", wantErr: true},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseMaintenanceSchedule(tt.schedule, tt.timezone)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMaintenanceSchedule(%q, %q) error = %v, want error %v", tt.schedule, tt.timezone, err, tt.wantErr)
+			continue
+		}
+		if err == nil && !schedule.Next(from).Equal(tt.want) {
+			t.Errorf("ParseMaintenanceSchedule(%q, %q) next = %s, want %s", tt.schedule, tt.timezone, schedule.Next(from), tt.want)
+		}
+	}
+}
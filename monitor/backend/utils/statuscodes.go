@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultAcceptedStatusCodes are the codes counted as up when a website
+// doesn't configure its own: normal responses, plus 403 and 429 which mean
+// the server is up but refusing us
+var DefaultAcceptedStatusCodes = []string{"200-399", "403", "429"}
+
+// ParseStatusCodeRange parses a status code ("404") or range ("200-299")
+func ParseStatusCodeRange(spec string) (low, high int, err error) {
+	spec = strings.TrimSpace(spec)
+	lowStr, highStr, isRange := strings.Cut(spec, "-")
+	if !isRange {
+		highStr = lowStr
+	}
+	if low, err = strconv.Atoi(strings.TrimSpace(lowStr)); err != nil {
+		return 0, 0, fmt.Errorf("invalid status code %q", spec)
+	}
+	if high, err = strconv.Atoi(strings.TrimSpace(highStr)); err != nil {
+		return 0, 0, fmt.Errorf("invalid status code %q", spec)
+	}
+	if low < 100 || high > 599 || low > high {
+		return 0, 0, fmt.Errorf("status code range %q must be within 100-599", spec)
+	}
+	return low, high, nil
+}
+
+// StatusCodeAccepted reports whether code matches any of the accepted codes
+// or ranges, falling back to DefaultAcceptedStatusCodes when none are given
+func StatusCodeAccepted(code int, accepted []string) bool {
+	if len(accepted) == 0 {
+		accepted = DefaultAcceptedStatusCodes
+	}
+	for _, spec := range accepted {
+		low, high, err := ParseStatusCodeRange(spec)
+		if err == nil && code >= low && code <= high {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package utils
+
+import "testing"
+
+func TestParseStatusCodeRange(t *testing.T) {
+	tests := []struct {
+		spec      string
+		low, high int
+		wantErr   bool
+	}{
+		{spec: "404", low: 404, high: 404},
+		{spec: "200-299", low: 200, high: 299},
+		{spec: " 200 - 399 ", low: 200, high: 399},
+		{spec: "100-599", low: 100, high: 599},
+		{spec: "", wantErr: true},
+		{spec: "abc", wantErr: true},
+		{spec: "200-", wantErr: true},
+		{spec: "-300", wantErr: true},
+		{spec: "99", wantErr: true},
+		{spec: "600", wantErr: true},
+		{spec: "300-200", wantErr: true},
+	}
+	for _, tt := range tests {
+		low, high, err := ParseStatusCodeRange(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStatusCodeRange(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if low != tt.low || high != tt.high {
+			t.Errorf("ParseStatusCodeRange(%q) = %d-%d, want %d-%d", tt.spec, low, high, tt.low, tt.high)
+		}
+	}
+}
+
+func TestStatusCodeAccepted(t *testing.T) {
+	tests := []struct {
+		code     int
+		accepted []string
+		want     bool
+	}{
+		{200, nil, true},
+		{302, nil, true},
+		{403, nil, true},
+		{429, nil, true},
+		{404, nil, false},
+		{500, nil, false},
+		{200, []string{"200"}, true},
+		{201, []string{"200"}, false},
+		{404, []string{"200-299", "404"}, true},
+		{503, []string{"500-599"}, true},
+		{403, []string{"200-299"}, false},
+		{200, []string{"bogus", "200-299"}, true},
+	}
+	for _, tt := range tests {
+		if got := StatusCodeAccepted(tt.code, tt.accepted); got != tt.want {
+			t.Errorf("StatusCodeAccepted(%d, %q) = %v, want %v", tt.code, tt.accepted, got, tt.want)
+		}
+	}
+}
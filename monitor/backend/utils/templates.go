@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Limits on user alert templates
+const (
+	MaxTitleTemplateLength = 256
+	MaxBodyTemplateLength  = 4000
+)
+
+// alertTemplateFuncs are the helpers available in alert templates
+var alertTemplateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+	// formatTime formats a Unix timestamp, e.g. {{formatTime .Incident.StartedAt "15:04 MST"}}
+	"formatTime": func(unix int64, layout string) string {
+		return time.Unix(unix, 0).UTC().Format(layout)
+	},
+	// default returns fallback when value is empty, e.g. {{default .Status.Error "unknown"}}
+	"default": func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// ParseAlertTemplate parses a user's alert template with the alert helpers
+func ParseAlertTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(alertTemplateFuncs).Option("missingkey=zero").Parse(text)
+}
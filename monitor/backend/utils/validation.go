@@ -2,8 +2,13 @@ package utils
 
 import (
 	"fmt"
+	"net"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
 )
 
 // ValidationError represents a validation error
@@ -41,8 +46,9 @@ func NormalizeURL(urlStr string) string {
 }
 
 // ValidateWebsite validates website input data
-func ValidateWebsite(name, urlStr string) ValidationErrors {
+func ValidateWebsite(website models.Website) ValidationErrors {
 	var errors ValidationErrors
+	name, urlStr := website.Name, website.URL
 
 	// Validate name
 	if strings.TrimSpace(name) == "" {
@@ -57,37 +63,479 @@ func ValidateWebsite(name, urlStr string) ValidationErrors {
 		})
 	}
 
-	// Validate URL
-	if strings.TrimSpace(urlStr) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "url",
-			Message: "Website URL is required",
-		})
-	} else {
-		// Parse URL to check if it's valid
-		parsedURL, err := url.Parse(strings.TrimSpace(urlStr))
-		if err != nil {
+	// Validate URL, or the host[:port] target of non-HTTP monitors
+	switch website.Type {
+	case "", models.MonitorHTTP:
+		if strings.TrimSpace(urlStr) == "" {
 			errors = append(errors, ValidationError{
 				Field:   "url",
-				Message: "Invalid URL format",
+				Message: "Website URL is required",
 			})
 		} else {
-			// Check if scheme is http or https
-			if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+			// Parse URL to check if it's valid
+			parsedURL, err := url.Parse(strings.TrimSpace(urlStr))
+			if err != nil {
 				errors = append(errors, ValidationError{
 					Field:   "url",
-					Message: "URL must start with http:// or https://",
+					Message: "Invalid URL format",
 				})
+			} else {
+				// Check if scheme is http or https
+				if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+					errors = append(errors, ValidationError{
+						Field:   "url",
+						Message: "URL must start with http:// or https://",
+					})
+				}
+				// Check if host exists
+				if parsedURL.Host == "" {
+					errors = append(errors, ValidationError{
+						Field:   "url",
+						Message: "URL must include a valid domain",
+					})
+				}
+			}
+		}
+	case models.MonitorTCP, models.MonitorTLS, models.MonitorDNS:
+		errors = append(errors, validateTarget(website)...)
+	case models.MonitorPush:
+		// The ping URL is generated, only the grace period is configurable
+		if website.GracePeriod < 0 || website.GracePeriod > 7*24*60*60 {
+			errors = append(errors, ValidationError{
+				Field:   "grace_period",
+				Message: "Grace period must be between 0 seconds and 7 days",
+			})
+		}
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "type",
+			Message: "Type must be one of http, tcp, dns, tls or push",
+		})
+	}
+
+	// Validate the check interval; 0 uses the default. Push monitors expect
+	// jobs such as weekly backups, so they may wait longer between pings.
+	maxInterval, maxIntervalText := 24*60*60, "24 hours"
+	if website.Type == models.MonitorPush {
+		maxInterval, maxIntervalText = 7*24*60*60, "7 days"
+	}
+	if website.Interval != 0 && (website.Interval < 30 || website.Interval > maxInterval) {
+		errors = append(errors, ValidationError{
+			Field:   "interval",
+			Message: fmt.Sprintf("Interval must be between 30 seconds and %s", maxIntervalText),
+		})
+	}
+
+	// Validate HTTP check definition
+	switch strings.ToUpper(website.Method) {
+	case "", "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "method",
+			Message: "Method must be one of GET, HEAD, POST, PUT, PATCH, DELETE or OPTIONS",
+		})
+	}
+	for key, value := range website.Headers {
+		if !validHeaderName(key) || strings.ContainsAny(value, "\r\n") {
+			errors = append(errors, ValidationError{
+				Field:   "headers",
+				Message: fmt.Sprintf("Invalid header %q", key),
+			})
+		}
+	}
+	if len(website.Body) > 64*1024 {
+		errors = append(errors, ValidationError{
+			Field:   "body",
+			Message: "Request body must be at most 64KB",
+		})
+	}
+	if website.Timeout < 0 || website.Timeout > 120 {
+		errors = append(errors, ValidationError{
+			Field:   "timeout",
+			Message: "Timeout must be between 1 and 120 seconds",
+		})
+	}
+	for _, spec := range website.AcceptedStatusCodes {
+		if _, _, err := ParseStatusCodeRange(spec); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   "accepted_status_codes",
+				Message: err.Error(),
+			})
+		}
+	}
+	if website.MaxRedirects < 0 || website.MaxRedirects > 30 {
+		errors = append(errors, ValidationError{
+			Field:   "max_redirects",
+			Message: "Max redirects must be between 0 and 30",
+		})
+	}
+
+	for i, assertion := range website.Assertions {
+		field := fmt.Sprintf("assertions[%d]", i)
+		switch assertion.Type {
+		case models.AssertContains, models.AssertNotContains:
+			if assertion.Value == "" {
+				errors = append(errors, ValidationError{Field: field + ".value", Message: "Keyword is required"})
+			}
+		case models.AssertRegex:
+			if _, err := regexp.Compile(assertion.Value); err != nil {
+				errors = append(errors, ValidationError{Field: field + ".value", Message: fmt.Sprintf("Invalid regex: %v", err)})
+			}
+		case models.AssertJSONPath:
+			if err := ValidateJSONPath(assertion.Path); err != nil {
+				errors = append(errors, ValidationError{Field: field + ".path", Message: err.Error()})
 			}
-			// Check if host exists
-			if parsedURL.Host == "" {
+			switch assertion.Operator {
+			case "", "eq", "ne", "gt", "gte", "lt", "lte", "contains":
+			default:
 				errors = append(errors, ValidationError{
-					Field:   "url",
-					Message: "URL must include a valid domain",
+					Field:   field + ".operator",
+					Message: "Operator must be one of eq, ne, gt, gte, lt, lte or contains",
+				})
+			}
+		case models.AssertMaxSize:
+			if size, err := strconv.ParseInt(assertion.Value, 10, 64); err != nil || size <= 0 || size > 10<<20 {
+				errors = append(errors, ValidationError{Field: field + ".value", Message: "Max size must be a byte count between 1 and 10485760"})
+			}
+		default:
+			errors = append(errors, ValidationError{
+				Field:   field + ".type",
+				Message: "Assertion type must be one of contains, not_contains, regex, json_path or max_size",
+			})
+		}
+	}
+
+	// Validate confirmation settings
+	if website.FailureThreshold < 0 || website.FailureThreshold > 10 {
+		errors = append(errors, ValidationError{
+			Field:   "failure_threshold",
+			Message: "Failure threshold must be between 1 and 10",
+		})
+	}
+	if website.RecoveryThreshold < 0 || website.RecoveryThreshold > 10 {
+		errors = append(errors, ValidationError{
+			Field:   "recovery_threshold",
+			Message: "Recovery threshold must be between 1 and 10",
+		})
+	}
+	if website.Retries != nil && (*website.Retries < 0 || *website.Retries > 5) {
+		errors = append(errors, ValidationError{
+			Field:   "retries",
+			Message: "Retries must be between 0 and 5",
+		})
+	}
+
+	if website.FlapThreshold < 0 || website.FlapThreshold > 100 {
+		errors = append(errors, ValidationError{
+			Field:   "flap_threshold",
+			Message: "Flap threshold must be a percentage between 1 and 100",
+		})
+	}
+
+	// Validate latency thresholds
+	if website.DegradedThresholdMs < 0 || website.DegradedThresholdMs > 300000 {
+		errors = append(errors, ValidationError{
+			Field:   "degraded_threshold_ms",
+			Message: "Degraded threshold must be between 1 and 300000 milliseconds",
+		})
+	}
+	if website.DegradedChecks < 0 || website.DegradedChecks > 100 {
+		errors = append(errors, ValidationError{
+			Field:   "degraded_checks",
+			Message: "Degraded checks must be between 1 and 100",
+		})
+	}
+	if website.DegradedWindow < 0 || website.DegradedWindow > 100 {
+		errors = append(errors, ValidationError{
+			Field:   "degraded_window",
+			Message: "Degraded window must be between 1 and 100 checks",
+		})
+	}
+
+	// Validate tags
+	if len(website.Tags) > 20 {
+		errors = append(errors, ValidationError{Field: "tags", Message: "At most 20 tags are allowed"})
+	}
+	for i, tag := range website.Tags {
+		if strings.TrimSpace(tag) == "" || len(tag) > 50 {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("tags[%d]", i),
+				Message: "Tags must be 1-50 characters",
+			})
+		}
+	}
+
+	return errors
+}
+
+// validateTarget validates the target and settings of TCP, TLS and DNS monitors
+func validateTarget(website models.Website) ValidationErrors {
+	var errors ValidationErrors
+
+	target := strings.TrimSpace(website.URL)
+	if i := strings.Index(target, "://"); i != -1 {
+		target = target[i+3:]
+	}
+	target = strings.TrimRight(target, "/")
+	if target == "" {
+		return append(errors, ValidationError{
+			Field:   "url",
+			Message: "Target host is required",
+		})
+	}
+
+	host, port, err := net.SplitHostPort(target)
+	switch {
+	case err == nil:
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			errors = append(errors, ValidationError{Field: "url", Message: "Port must be between 1 and 65535"})
+		}
+	case website.Type == models.MonitorTCP:
+		errors = append(errors, ValidationError{Field: "url", Message: "TCP targets must be host:port"})
+	default:
+		host = strings.Trim(target, "[]")
+	}
+	if host == "" || strings.ContainsAny(host, " /?#@") {
+		errors = append(errors, ValidationError{Field: "url", Message: "Target must include a valid host"})
+	}
+
+	if website.Type == models.MonitorTLS {
+		switch strings.ToLower(website.TLSProtocol) {
+		case "", models.TLSDirect, models.TLSSMTP, models.TLSIMAP, models.TLSPOP3, models.TLSLDAP, models.TLSPostgres:
+		default:
+			errors = append(errors, ValidationError{
+				Field:   "tls_protocol",
+				Message: "TLS protocol must be one of tls, smtp, imap, pop3, ldap or postgres",
+			})
+		}
+	}
+
+	if website.Type == models.MonitorDNS {
+		switch strings.ToUpper(website.DNSRecordType) {
+		case "", "A", "AAAA", "CNAME", "MX", "NS", "TXT":
+		default:
+			errors = append(errors, ValidationError{
+				Field:   "dns_record_type",
+				Message: "DNS record type must be one of A, AAAA, CNAME, MX, NS or TXT",
+			})
+		}
+		if resolver := strings.TrimSpace(website.DNSResolver); resolver != "" {
+			resolverHost := resolver
+			if h, _, err := net.SplitHostPort(resolver); err == nil {
+				resolverHost = h
+			}
+			if resolverHost == "" || strings.ContainsAny(resolverHost, " /?#@") {
+				errors = append(errors, ValidationError{Field: "dns_resolver", Message: "Resolver must be host or host:port"})
+			}
+		}
+	}
+
+	return errors
+}
+
+// validHeaderName reports whether name is a valid HTTP header field name
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 127 || r <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateNotificationChannels validates a user's notification channels
+func ValidateNotificationChannels(channels []models.NotificationChannel) ValidationErrors {
+	var errors ValidationErrors
+
+	seen := make(map[string]bool)
+	for i, ch := range channels {
+		field := fmt.Sprintf("notification_channels[%d]", i)
+		require := func(name, value, message string) {
+			if strings.TrimSpace(value) == "" {
+				errors = append(errors, ValidationError{Field: field + "." + name, Message: message})
+			}
+		}
+		requireURL := func(value, message string) {
+			parsedURL, err := url.Parse(strings.TrimSpace(value))
+			if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+				errors = append(errors, ValidationError{Field: field + ".url", Message: message})
+			}
+		}
+
+		if ch.ID != "" {
+			if seen[ch.ID] {
+				errors = append(errors, ValidationError{Field: field + ".id", Message: "Channel IDs must be unique"})
+			}
+			seen[ch.ID] = true
+		}
+		errors = append(errors, ValidateAlertTemplates(field, ch.TitleTemplate, ch.BodyTemplate)...)
+		for _, name := range ch.RedactedFields() {
+			errors = append(errors, ValidationError{
+				Field:   field + "." + name,
+				Message: "Secrets must be entered again when the channel's destination changes",
+			})
+		}
+
+		switch ch.Type {
+		case models.ChannelDiscord, models.ChannelSlack, models.ChannelTeams, models.ChannelWebhook:
+			requireURL(ch.URL, "A valid http(s) webhook URL is required")
+		case models.ChannelTelegram:
+			require("token", ch.Token, "Telegram bot token is required")
+			require("chat_id", ch.ChatID, "Telegram chat ID is required")
+			if ch.URL != "" {
+				requireURL(ch.URL, "Telegram API URL must be a valid http(s) URL")
+			}
+		case models.ChannelEmail:
+			require("smtp_host", ch.SMTPHost, "SMTP host is required")
+			require("from", ch.From, "Sender address is required")
+			if len(ch.To) == 0 {
+				errors = append(errors, ValidationError{Field: field + ".to", Message: "At least one recipient is required"})
+			}
+			if ch.SMTPPort < 0 || ch.SMTPPort > 65535 {
+				errors = append(errors, ValidationError{Field: field + ".smtp_port", Message: "SMTP port must be between 1 and 65535"})
+			}
+		case models.ChannelNtfy:
+			requireURL(ch.URL, "A valid ntfy server URL is required")
+			require("topic", ch.Topic, "ntfy topic is required")
+		case models.ChannelGotify:
+			requireURL(ch.URL, "A valid Gotify server URL is required")
+			require("token", ch.Token, "Gotify app token is required")
+		default:
+			errors = append(errors, ValidationError{
+				Field:   field + ".type",
+				Message: fmt.Sprintf("Unknown channel type %q", ch.Type),
+			})
+		}
+	}
+
+	return errors
+}
+
+// ValidateExpiryThresholds checks a user's certificate or domain expiry
+// thresholds, sent as the named field
+func ValidateExpiryThresholds(name string, thresholds []int) ValidationErrors {
+	var errors ValidationErrors
+	if len(thresholds) > 10 {
+		errors = append(errors, ValidationError{Field: name, Message: "At most 10 thresholds are allowed"})
+	}
+	seen := make(map[int]bool)
+	for i, days := range thresholds {
+		field := fmt.Sprintf("%s[%d]", name, i)
+		if days < 1 || days > 365 {
+			errors = append(errors, ValidationError{Field: field, Message: "Thresholds must be between 1 and 365 days"})
+		}
+		if seen[days] {
+			errors = append(errors, ValidationError{Field: field, Message: "Thresholds must be unique"})
+		}
+		seen[days] = true
+	}
+	return errors
+}
+
+// ValidateEscalationPolicies checks a user's escalation policies against
+// the IDs of the user's notification channels
+func ValidateEscalationPolicies(policies []models.EscalationPolicy, channelIDs map[string]bool) ValidationErrors {
+	var errors ValidationErrors
+
+	seen := make(map[string]bool)
+	for i, policy := range policies {
+		field := fmt.Sprintf("escalation_policies[%d]", i)
+
+		if policy.ID != "" {
+			if seen[policy.ID] {
+				errors = append(errors, ValidationError{Field: field + ".id", Message: "Policy IDs must be unique"})
+			}
+			seen[policy.ID] = true
+		}
+		if len(policy.Steps) == 0 || len(policy.Steps) > 10 {
+			errors = append(errors, ValidationError{Field: field + ".steps", Message: "A policy needs between 1 and 10 steps"})
+		}
+		if policy.RepeatMinutes != 0 && (policy.RepeatMinutes < 5 || policy.RepeatMinutes > 1440) {
+			errors = append(errors, ValidationError{
+				Field:   field + ".repeat_minutes",
+				Message: "Repeat interval must be 0 (no reminders) or between 5 and 1440 minutes",
+			})
+		}
+
+		previous := 0
+		for j, step := range policy.Steps {
+			stepField := fmt.Sprintf("%s.steps[%d]", field, j)
+			if step.AfterMinutes < previous || step.AfterMinutes > 10080 {
+				errors = append(errors, ValidationError{
+					Field:   stepField + ".after_minutes",
+					Message: "Steps must be in order, between 0 and 10080 minutes after the incident opens",
 				})
 			}
+			previous = step.AfterMinutes
+			if len(step.ChannelIDs) == 0 {
+				errors = append(errors, ValidationError{Field: stepField + ".channel_ids", Message: "At least one channel is required"})
+			}
+			for _, id := range step.ChannelIDs {
+				if !channelIDs[id] {
+					errors = append(errors, ValidationError{
+						Field:   stepField + ".channel_ids",
+						Message: fmt.Sprintf("Unknown notification channel %q", id),
+					})
+				}
+			}
+		}
+	}
+
+	return errors
+}
+
+// ValidateAlertTemplates checks that a channel's alert templates parse
+func ValidateAlertTemplates(field, titleTemplate, bodyTemplate string) ValidationErrors {
+	var errors ValidationErrors
+	check := func(name, text string, maxLength int) {
+		if len(text) > maxLength {
+			errors = append(errors, ValidationError{
+				Field:   field + "." + name,
+				Message: fmt.Sprintf("Template must be at most %d characters", maxLength),
+			})
+			return
+		}
+		if _, err := ParseAlertTemplate(name, text); err != nil {
+			errors = append(errors, ValidationError{Field: field + "." + name, Message: fmt.Sprintf("Invalid template: %v", err)})
 		}
 	}
+	check("title_template", titleTemplate, MaxTitleTemplateLength)
+	check("body_template", bodyTemplate, MaxBodyTemplateLength)
+	return errors
+}
+
+// ValidateMaintenanceWindow validates a maintenance window
+func ValidateMaintenanceWindow(window models.MaintenanceWindow) ValidationErrors {
+	var errors ValidationErrors
+
+	if strings.TrimSpace(window.Name) == "" || len(window.Name) > 100 {
+		errors = append(errors, ValidationError{Field: "name", Message: "Name is required and must be at most 100 characters"})
+	}
+	if len(window.WebsiteIDs) == 0 && len(window.Tags) == 0 {
+		errors = append(errors, ValidationError{Field: "website_ids", Message: "Select at least one website or tag"})
+	}
+
+	switch {
+	case window.Schedule != "":
+		if window.StartsAt != 0 || window.EndsAt != 0 {
+			errors = append(errors, ValidationError{Field: "schedule", Message: "A window is either recurring (schedule) or one-off (starts_at/ends_at), not both"})
+		}
+		if _, err := ParseMaintenanceSchedule(window.Schedule, window.Timezone); err != nil {
+			errors = append(errors, ValidationError{Field: "schedule", Message: fmt.Sprintf("Invalid schedule: %v", err)})
+		}
+		if window.DurationMinutes < 1 || window.DurationMinutes > 7*24*60 {
+			errors = append(errors, ValidationError{Field: "duration_minutes", Message: "Duration must be between 1 minute and 7 days"})
+		}
+	case window.StartsAt != 0 || window.EndsAt != 0:
+		if window.EndsAt <= window.StartsAt {
+			errors = append(errors, ValidationError{Field: "ends_at", Message: "End must be after start"})
+		}
+	default:
+		errors = append(errors, ValidationError{Field: "schedule", Message: "Set either starts_at and ends_at, or schedule and duration_minutes"})
+	}
 
 	return errors
-}
\ No newline at end of file
+}
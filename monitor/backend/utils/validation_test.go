@@ -0,0 +1,164 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prateeks007/PulseWatch/monitor/backend/models"
+)
+
+func TestValidateNotificationChannels(t *testing.T) {
+	tests := []struct {
+		name     string
+		channels []models.NotificationChannel
+		want     []string // Fields with errors, in order
+	}{
+		{
+			name: "valid channels",
+			channels: []models.NotificationChannel{
+				{ID: "a", Type: models.ChannelDiscord, URL: "https://discord.com/api/webhooks/1/x"},
+				{ID: "b", Type: models.ChannelSlack, URL: "https://hooks.slack.com/services/x"},
+				{ID: "c", Type: models.ChannelTeams, URL: "https://example.webhook.office.com/x"},
+				{ID: "d", Type: models.ChannelTelegram, Token: "123:abc", ChatID: "42"},
+				{ID: "e", Type: models.ChannelEmail, SMTPHost: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}},
+				{ID: "f", Type: models.ChannelNtfy, URL: "https://ntfy.sh", Topic: "alerts"},
+				{ID: "g", Type: models.ChannelGotify, URL: "https://gotify.example.com", Token: "t"},
+				{ID: "h", Type: models.ChannelWebhook, URL: "http://localhost:8080/hook"},
+			},
+		},
+		{
+			name:     "no channels",
+			channels: nil,
+		},
+		{
+			name:     "unknown type",
+			channels: []models.NotificationChannel{{Type: "pager"}},
+			want:     []string{"notification_channels[0].type"},
+		},
+		{
+			name: "duplicate IDs",
+			channels: []models.NotificationChannel{
+				{ID: "a", Type: models.ChannelSlack, URL: "https://hooks.slack.com/a"},
+				{ID: "a", Type: models.ChannelSlack, URL: "https://hooks.slack.com/b"},
+			},
+			want: []string{"notification_channels[1].id"},
+		},
+		{
+			name: "webhook URLs must be http(s)",
+			channels: []models.NotificationChannel{
+				{Type: models.ChannelDiscord},
+				{Type: models.ChannelWebhook, URL: "ftp://example.com/hook"},
+				{Type: models.ChannelTeams, URL: "https://"},
+			},
+			want: []string{"notification_channels[0].url", "notification_channels[1].url", "notification_channels[2].url"},
+		},
+		{
+			name: "telegram needs a token and chat",
+			channels: []models.NotificationChannel{
+				{Type: models.ChannelTelegram, Token: " ", URL: "api.telegram.org"},
+			},
+			want: []string{"notification_channels[0].token", "notification_channels[0].chat_id", "notification_channels[0].url"},
+		},
+		{
+			name: "email needs a host, sender and recipients",
+			channels: []models.NotificationChannel{
+				{Type: models.ChannelEmail, SMTPPort: 70000},
+			},
+			want: []string{
+				"notification_channels[0].smtp_host", "notification_channels[0].from",
+				"notification_channels[0].to", "notification_channels[0].smtp_port",
+			},
+		},
+		{
+			name: "ntfy needs a server and topic",
+			channels: []models.NotificationChannel{
+				{Type: models.ChannelNtfy},
+			},
+			want: []string{"notification_channels[0].url", "notification_channels[0].topic"},
+		},
+		{
+			name: "gotify needs a server and token",
+			channels: []models.NotificationChannel{
+				{Type: models.ChannelGotify, URL: "https://gotify.example.com"},
+			},
+			want: []string{"notification_channels[0].token"},
+		},
+		{
+			name: "redacted secrets must be entered again",
+			channels: []models.NotificationChannel{
+				{Type: models.ChannelDiscord, URL: "https://discord.com/" + models.RedactedSecret},
+				{Type: models.ChannelGotify, URL: "https://gotify.example.com", Token: models.RedactedSecret},
+			},
+			want: []string{"notification_channels[0].url", "notification_channels[1].token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, err := range ValidateNotificationChannels(tt.channels) {
+				got = append(got, err.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("error fields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWebsiteInterval(t *testing.T) {
+	tests := []struct {
+		website models.Website
+		wantErr bool
+	}{
+		{models.Website{}, false},
+		{models.Website{Interval: 30}, false},
+		{models.Website{Interval: 86400}, false},
+		{models.Website{Interval: 10}, true},
+		{models.Website{Interval: -60}, true},
+		{models.Website{Interval: 86401}, true},
+		{models.Website{Type: models.MonitorPush, Interval: 7 * 86400}, false},
+		{models.Website{Type: models.MonitorPush, Interval: 7*86400 + 1}, true},
+	}
+	for _, tt := range tests {
+		tt.website.Name = "Example"
+		if tt.website.Type == "" {
+			tt.website.URL = "https://example.com"
+		}
+		errors := ValidateWebsite(tt.website)
+		gotErr := len(errors) == 1 && errors[0].Field == "interval"
+		if gotErr != tt.wantErr || (!tt.wantErr && len(errors) > 0) {
+			t.Errorf("ValidateWebsite(%s every %ds) = %+v, want interval error %v", tt.website.Type, tt.website.Interval, errors, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateAlertTemplates(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		body  string
+		want  []string // Fields with errors, in order
+	}{
+		{name: "no templates"},
+		{name: "valid", title: "{{upper .Website.Name}} is {{.Event}}", body: `{{default .LastError "none"}}`},
+		{name: "unknown helper", title: "{{shout .Website.Name}}", want: []string{"ch.title_template"}},
+		{name: "unclosed action", body: "{{if .IsUp}}up", want: []string{"ch.body_template"}},
+		{
+			name:  "too long",
+			title: strings.Repeat("x", MaxTitleTemplateLength+1),
+			body:  strings.Repeat("x", MaxBodyTemplateLength+1),
+			want:  []string{"ch.title_template", "ch.body_template"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, err := range ValidateAlertTemplates("ch", tt.title, tt.body) {
+			got = append(got, err.Field)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: error fields = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -51,6 +51,9 @@ export default function SSLCard({ website }) {
     } else if (ssl.days_left <= 14) {
       badgeText = `Expires in ${ssl.days_left}d`;
       badgeCls = "inline-block px-2 py-0.5 rounded-full text-xs bg-amber-100 text-amber-700 dark:bg-amber-900/40 dark:text-amber-400";
+    } else if (ssl.verification_error) {
+      badgeText = `Untrusted (${ssl.days_left}d left)`;
+      badgeCls = "inline-block px-2 py-0.5 rounded-full text-xs bg-amber-100 text-amber-700 dark:bg-amber-900/40 dark:text-amber-400";
     } else {
       badgeText = `Valid (${ssl.days_left}d left)`;
       badgeCls = "inline-block px-2 py-0.5 rounded-full text-xs bg-emerald-100 text-emerald-700 dark:bg-emerald-900/40 dark:text-emerald-400";